@@ -0,0 +1,192 @@
+package maxicode
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// eciUTF8 designates UTF-8, its bytes are written as they are.
+const eciUTF8 = 26
+
+// charset is a single byte character set designated by an ECI. The bytes
+// below 0x80 are ASCII in all of them, high holds the rest.
+type charset struct {
+	eci  int
+	name string
+	high [128]rune
+}
+
+// charsetBytes maps runes back to bytes, in the same order as charsets.
+var charsetBytes = buildCharsetBytes()
+
+func buildCharsetBytes() []map[rune]byte {
+	tables := make([]map[rune]byte, len(charsets))
+
+	for i, cs := range charsets {
+		tables[i] = make(map[rune]byte, 128)
+
+		for b, r := range cs.high {
+			if r != utf8.RuneError {
+				tables[i][r] = byte(0x80 + b)
+			}
+		}
+	}
+
+	return tables
+}
+
+func charsetIndex(eci int) int {
+	for i, cs := range charsets {
+		if cs.eci == eci {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func encodeRune(cs int, r rune) (byte, bool) {
+	if r < 0x80 {
+		return byte(r), true
+	}
+
+	b, ok := charsetBytes[cs][r]
+
+	return b, ok
+}
+
+// transcodeSegments turns segments holding UTF-8 text into the bytes of their
+// character sets. Segments without an ECI are split into as few runs as
+// possible, each in the first character set that holds it. Offsets in errors
+// count bytes from the start of the first segment.
+func transcodeSegments(segments []Segment) ([]Segment, error) {
+	var transcoded []Segment
+
+	offset := 0
+	for _, segment := range segments {
+		for i := 0; i < len(segment.Data); {
+			r, n := utf8.DecodeRuneInString(segment.Data[i:])
+			if r == utf8.RuneError && n == 1 {
+				return nil, newEncodeError(ErrInvalidUTF8, FieldSecondary, offset+i, fmt.Sprintf("input data is not valid UTF-8 at offset %d", offset+i))
+			}
+
+			i += n
+		}
+
+		var err error
+
+		switch segment.ECI {
+		case 0:
+			transcoded, err = appendAutoSegments(transcoded, segment.Data, offset)
+		case eciUTF8:
+			transcoded = append(transcoded, segment)
+		default:
+			transcoded, err = appendCharsetSegment(transcoded, segment, offset)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		offset += len(segment.Data)
+	}
+
+	return transcoded, nil
+}
+
+func appendCharsetSegment(segments []Segment, segment Segment, offset int) ([]Segment, error) {
+	cs := charsetIndex(segment.ECI)
+	if cs == -1 {
+		return nil, newEncodeError(ErrInvalidECI, FieldECI, offset, fmt.Sprintf("ECI %d is not a supported character set", segment.ECI))
+	}
+
+	var sb strings.Builder
+	for i, r := range segment.Data {
+		b, ok := encodeRune(cs, r)
+		if !ok {
+			return nil, newEncodeError(ErrUnrepresentable, FieldSecondary, offset+i, fmt.Sprintf("%q at offset %d cannot be represented in %s", r, offset+i, charsets[cs].name))
+		}
+
+		sb.WriteByte(b)
+	}
+
+	return append(segments, Segment{ECI: segment.ECI, Data: sb.String()}), nil
+}
+
+// appendAutoSegments picks the character set that holds the longest run of
+// the remaining text each time, which gives the fewest ECI switches.
+func appendAutoSegments(segments []Segment, text string, offset int) ([]Segment, error) {
+	for i := 0; i < len(text); {
+		best, bestLen := -1, 0
+
+		for cs := range charsets {
+			n := 0
+			for _, r := range text[i:] {
+				if _, ok := encodeRune(cs, r); !ok {
+					break
+				}
+
+				n += utf8.RuneLen(r)
+			}
+
+			if n > bestLen {
+				best, bestLen = cs, n
+			}
+		}
+
+		if best == -1 {
+			r, _ := utf8.DecodeRuneInString(text[i:])
+			return nil, newEncodeError(ErrUnrepresentable, FieldSecondary, offset+i, fmt.Sprintf("%q at offset %d cannot be represented in any supported character set", r, offset+i))
+		}
+
+		var sb strings.Builder
+		for _, r := range text[i : i+bestLen] {
+			b, _ := encodeRune(best, r)
+			sb.WriteByte(b)
+		}
+
+		// ISO-8859-1 is what readers assume before any ECI, so the message
+		// does not need to start with a designator for it.
+		eci := charsets[best].eci
+		if eci == 3 && (len(segments) == 0 || segments[len(segments)-1].ECI == 0) {
+			eci = 0
+		}
+
+		segments = append(segments, Segment{ECI: eci, Data: sb.String()})
+		i += bestLen
+	}
+
+	return segments, nil
+}
+
+// Text returns the decoded data as UTF-8, converting every segment from the
+// character set its ECI designates. Data without an ECI is read as ISO-8859-1.
+func (r *Result) Text() (string, error) {
+	var sb strings.Builder
+
+	for _, segment := range r.Segments {
+		switch segment.ECI {
+		case eciUTF8:
+			sb.WriteString(segment.Data)
+			continue
+		case 0:
+			segment.ECI = 3
+		}
+
+		cs := charsetIndex(segment.ECI)
+		if cs == -1 {
+			return "", fmt.Errorf("ECI %d is not a supported character set", segment.ECI)
+		}
+
+		for i := 0; i < len(segment.Data); i++ {
+			if b := segment.Data[i]; b < 0x80 {
+				sb.WriteByte(b)
+			} else {
+				sb.WriteRune(charsets[cs].high[b-0x80])
+			}
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,125 @@
+package maxicode
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncodeUTF8(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		segments    []Segment
+		expSegments []Segment
+		expErr      string
+	}{
+		{
+			desc:        "ascii needs no ECI",
+			segments:    []Segment{{Data: "PLAIN ASCII TEXT"}},
+			expSegments: []Segment{{Data: "PLAIN ASCII TEXT"}},
+		},
+		{
+			desc:        "latin-1 is the default",
+			segments:    []Segment{{Data: "Kölner Straße 5"}},
+			expSegments: []Segment{{Data: "K\xf6lner Stra\xdfe 5"}},
+		},
+		{
+			desc:        "latin-2",
+			segments:    []Segment{{Data: "Łódź, ul. Piotrkowska 1"}},
+			expSegments: []Segment{{ECI: 4, Data: "\xa3\xf3d\xbc, ul. Piotrkowska 1"}},
+		},
+		{
+			desc:        "one character set holding polish and danish",
+			segments:    []Segment{{Data: "Łódź / Ærøskøbing"}},
+			expSegments: []Segment{{ECI: 15, Data: "\xd9\xf3d\xea / \xafr\xb8sk\xb8bing"}},
+		},
+		{
+			desc:     "latin-2 followed by latin-1",
+			segments: []Segment{{Data: "Łódź / España"}},
+			expSegments: []Segment{
+				{ECI: 4, Data: "\xa3\xf3d\xbc / Espa"},
+				{ECI: 3, Data: "\xf1a"},
+			},
+		},
+		{
+			desc:        "cyrillic",
+			segments:    []Segment{{Data: "Москва"}},
+			expSegments: []Segment{{ECI: 7, Data: "\xbc\xde\xe1\xda\xd2\xd0"}},
+		},
+		{
+			desc:        "explicit ECI",
+			segments:    []Segment{{ECI: 22, Data: "Москва"}},
+			expSegments: []Segment{{ECI: 22, Data: "\xcc\xee\xf1\xea\xe2\xe0"}},
+		},
+		{
+			desc:        "UTF-8 ECI keeps the bytes",
+			segments:    []Segment{{ECI: 26, Data: "東京"}},
+			expSegments: []Segment{{ECI: 26, Data: "東京"}},
+		},
+		{
+			desc:     "rune missing from explicit ECI",
+			segments: []Segment{{ECI: 4, Data: "Ærø"}},
+			expErr:   "'Æ' at offset 0 cannot be represented in ISO-8859-2",
+		},
+		{
+			desc:     "unsupported ECI",
+			segments: []Segment{{ECI: 20, Data: "東京"}},
+			expErr:   "ECI 20 is not a supported character set",
+		},
+		{
+			desc:     "unrepresentable rune",
+			segments: []Segment{{Data: "TOKYO 東京"}},
+			expErr:   "'東' at offset 6 cannot be represented in any supported character set",
+		},
+		{
+			desc:     "invalid UTF-8",
+			segments: []Segment{{Data: "K\xf6LN"}},
+			expErr:   "input data is not valid UTF-8 at offset 1",
+		},
+	}
+
+	encoder, err := NewEncoder(Mode4, WithUTF8())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			grids, err := encoder.EncodeSegments(tc.segments)
+			if tc.expErr != "" {
+				if err == nil || err.Error() != tc.expErr {
+					t.Fatalf("expected error %q, got %v", tc.expErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Decode(grids[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expSegments, result.Segments); diff != "" {
+				t.Errorf("Decoded segments are not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+
+			text, err := result.Text()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expText := ""
+			for _, segment := range tc.segments {
+				expText += segment.Data
+			}
+
+			if text != expText {
+				t.Errorf("expected text %q, got %q", expText, text)
+			}
+		})
+	}
+}
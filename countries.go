@@ -0,0 +1,108 @@
+package maxicode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Country is an ISO 3166-1 country.
+type Country struct {
+	Numeric int
+	Alpha2  string
+	Alpha3  string
+	Name    string
+}
+
+// PostcodeFormat is the kind of postcodes a country uses.
+type PostcodeFormat int
+
+const (
+	PostcodeNumeric PostcodeFormat = iota
+	PostcodeAlphanumeric
+	PostcodeNone
+)
+
+// postcodeFormats holds the countries whose postcodes are not numeric, by
+// their alpha-2 code.
+var postcodeFormats = map[string]PostcodeFormat{
+	"AD": PostcodeAlphanumeric, "AI": PostcodeAlphanumeric, "AR": PostcodeAlphanumeric,
+	"BB": PostcodeAlphanumeric, "BM": PostcodeAlphanumeric, "BN": PostcodeAlphanumeric,
+	"CA": PostcodeAlphanumeric, "FK": PostcodeAlphanumeric, "GB": PostcodeAlphanumeric,
+	"GG": PostcodeAlphanumeric, "GI": PostcodeAlphanumeric, "GS": PostcodeAlphanumeric,
+	"IE": PostcodeAlphanumeric, "IM": PostcodeAlphanumeric, "IO": PostcodeAlphanumeric,
+	"JE": PostcodeAlphanumeric, "JM": PostcodeAlphanumeric, "KY": PostcodeAlphanumeric,
+	"KZ": PostcodeAlphanumeric, "LC": PostcodeAlphanumeric, "MS": PostcodeAlphanumeric,
+	"MT": PostcodeAlphanumeric, "NL": PostcodeAlphanumeric, "PN": PostcodeAlphanumeric,
+	"SH": PostcodeAlphanumeric, "SO": PostcodeAlphanumeric, "SZ": PostcodeAlphanumeric,
+	"TC": PostcodeAlphanumeric, "VC": PostcodeAlphanumeric, "VG": PostcodeAlphanumeric,
+
+	"AE": PostcodeNone, "AG": PostcodeNone, "AO": PostcodeNone, "AQ": PostcodeNone,
+	"AW": PostcodeNone, "BF": PostcodeNone, "BI": PostcodeNone, "BJ": PostcodeNone,
+	"BO": PostcodeNone, "BQ": PostcodeNone, "BS": PostcodeNone, "BV": PostcodeNone,
+	"BW": PostcodeNone, "BZ": PostcodeNone, "CD": PostcodeNone, "CF": PostcodeNone,
+	"CG": PostcodeNone, "CI": PostcodeNone, "CK": PostcodeNone, "CM": PostcodeNone,
+	"CW": PostcodeNone, "DJ": PostcodeNone, "DM": PostcodeNone, "ER": PostcodeNone,
+	"FJ": PostcodeNone, "GA": PostcodeNone, "GD": PostcodeNone, "GH": PostcodeNone,
+	"GM": PostcodeNone, "GQ": PostcodeNone, "GY": PostcodeNone, "HK": PostcodeNone,
+	"HM": PostcodeNone, "KI": PostcodeNone, "KM": PostcodeNone, "KN": PostcodeNone,
+	"KP": PostcodeNone, "LY": PostcodeNone, "ML": PostcodeNone, "MO": PostcodeNone,
+	"MR": PostcodeNone, "NR": PostcodeNone, "NU": PostcodeNone, "QA": PostcodeNone,
+	"RW": PostcodeNone, "SB": PostcodeNone, "SC": PostcodeNone, "SL": PostcodeNone,
+	"SR": PostcodeNone, "SS": PostcodeNone, "ST": PostcodeNone, "SX": PostcodeNone,
+	"SY": PostcodeNone, "TD": PostcodeNone, "TF": PostcodeNone, "TG": PostcodeNone,
+	"TK": PostcodeNone, "TL": PostcodeNone, "TO": PostcodeNone, "TV": PostcodeNone,
+	"UG": PostcodeNone, "VU": PostcodeNone, "YE": PostcodeNone, "ZW": PostcodeNone,
+}
+
+// countryIndex maps numeric, alpha-2 and alpha-3 codes to countries.
+var countryIndex = buildCountryIndex()
+
+func buildCountryIndex() map[string]int {
+	index := make(map[string]int, 3*len(countries))
+
+	for i, c := range countries {
+		index[fmt.Sprintf("%03d", c.Numeric)] = i
+		index[c.Alpha2] = i
+		index[c.Alpha3] = i
+	}
+
+	return index
+}
+
+// LookupCountry returns the country with the given ISO 3166-1 alpha-2,
+// alpha-3 or numeric code, such as "DE", "DEU" or "276".
+func LookupCountry(code string) (Country, error) {
+	key := strings.ToUpper(code)
+	if n, err := strconv.Atoi(code); err == nil && isDigits(code) {
+		key = fmt.Sprintf("%03d", n)
+	}
+
+	i, ok := countryIndex[key]
+	if !ok {
+		return Country{}, newEncodeError(ErrInvalidCountry, FieldCountry, -1, fmt.Sprintf("%q is not an ISO 3166 country code", code))
+	}
+
+	return countries[i], nil
+}
+
+// PostcodeFormat returns the kind of postcodes the country uses.
+func (c Country) PostcodeFormat() PostcodeFormat {
+	return postcodeFormats[c.Alpha2]
+}
+
+// Mode returns the mode that carries the postcodes of the country, Mode2 for
+// numeric postcodes and Mode3 otherwise.
+func (c Country) Mode() Mode {
+	if c.PostcodeFormat() == PostcodeNumeric {
+		return Mode2
+	}
+
+	return Mode3
+}
+
+func knownCountry(numeric int) bool {
+	_, ok := countryIndex[fmt.Sprintf("%03d", numeric)]
+
+	return ok
+}
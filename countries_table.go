@@ -0,0 +1,255 @@
+// Code generated by gen_countries.go; DO NOT EDIT.
+
+package maxicode
+
+var countries = []Country{
+	{Numeric: 4, Alpha2: "AF", Alpha3: "AFG", Name: "Afghanistan"},
+	{Numeric: 8, Alpha2: "AL", Alpha3: "ALB", Name: "Albania"},
+	{Numeric: 10, Alpha2: "AQ", Alpha3: "ATA", Name: "Antarctica"},
+	{Numeric: 12, Alpha2: "DZ", Alpha3: "DZA", Name: "Algeria"},
+	{Numeric: 16, Alpha2: "AS", Alpha3: "ASM", Name: "American Samoa"},
+	{Numeric: 20, Alpha2: "AD", Alpha3: "AND", Name: "Andorra"},
+	{Numeric: 24, Alpha2: "AO", Alpha3: "AGO", Name: "Angola"},
+	{Numeric: 28, Alpha2: "AG", Alpha3: "ATG", Name: "Antigua and Barbuda"},
+	{Numeric: 31, Alpha2: "AZ", Alpha3: "AZE", Name: "Azerbaijan"},
+	{Numeric: 32, Alpha2: "AR", Alpha3: "ARG", Name: "Argentina"},
+	{Numeric: 36, Alpha2: "AU", Alpha3: "AUS", Name: "Australia"},
+	{Numeric: 40, Alpha2: "AT", Alpha3: "AUT", Name: "Austria"},
+	{Numeric: 44, Alpha2: "BS", Alpha3: "BHS", Name: "Bahamas"},
+	{Numeric: 48, Alpha2: "BH", Alpha3: "BHR", Name: "Bahrain"},
+	{Numeric: 50, Alpha2: "BD", Alpha3: "BGD", Name: "Bangladesh"},
+	{Numeric: 51, Alpha2: "AM", Alpha3: "ARM", Name: "Armenia"},
+	{Numeric: 52, Alpha2: "BB", Alpha3: "BRB", Name: "Barbados"},
+	{Numeric: 56, Alpha2: "BE", Alpha3: "BEL", Name: "Belgium"},
+	{Numeric: 60, Alpha2: "BM", Alpha3: "BMU", Name: "Bermuda"},
+	{Numeric: 64, Alpha2: "BT", Alpha3: "BTN", Name: "Bhutan"},
+	{Numeric: 68, Alpha2: "BO", Alpha3: "BOL", Name: "Bolivia, Plurinational State of"},
+	{Numeric: 70, Alpha2: "BA", Alpha3: "BIH", Name: "Bosnia and Herzegovina"},
+	{Numeric: 72, Alpha2: "BW", Alpha3: "BWA", Name: "Botswana"},
+	{Numeric: 74, Alpha2: "BV", Alpha3: "BVT", Name: "Bouvet Island"},
+	{Numeric: 76, Alpha2: "BR", Alpha3: "BRA", Name: "Brazil"},
+	{Numeric: 84, Alpha2: "BZ", Alpha3: "BLZ", Name: "Belize"},
+	{Numeric: 86, Alpha2: "IO", Alpha3: "IOT", Name: "British Indian Ocean Territory"},
+	{Numeric: 90, Alpha2: "SB", Alpha3: "SLB", Name: "Solomon Islands"},
+	{Numeric: 92, Alpha2: "VG", Alpha3: "VGB", Name: "Virgin Islands, British"},
+	{Numeric: 96, Alpha2: "BN", Alpha3: "BRN", Name: "Brunei Darussalam"},
+	{Numeric: 100, Alpha2: "BG", Alpha3: "BGR", Name: "Bulgaria"},
+	{Numeric: 104, Alpha2: "MM", Alpha3: "MMR", Name: "Myanmar"},
+	{Numeric: 108, Alpha2: "BI", Alpha3: "BDI", Name: "Burundi"},
+	{Numeric: 112, Alpha2: "BY", Alpha3: "BLR", Name: "Belarus"},
+	{Numeric: 116, Alpha2: "KH", Alpha3: "KHM", Name: "Cambodia"},
+	{Numeric: 120, Alpha2: "CM", Alpha3: "CMR", Name: "Cameroon"},
+	{Numeric: 124, Alpha2: "CA", Alpha3: "CAN", Name: "Canada"},
+	{Numeric: 132, Alpha2: "CV", Alpha3: "CPV", Name: "Cabo Verde"},
+	{Numeric: 136, Alpha2: "KY", Alpha3: "CYM", Name: "Cayman Islands"},
+	{Numeric: 140, Alpha2: "CF", Alpha3: "CAF", Name: "Central African Republic"},
+	{Numeric: 144, Alpha2: "LK", Alpha3: "LKA", Name: "Sri Lanka"},
+	{Numeric: 148, Alpha2: "TD", Alpha3: "TCD", Name: "Chad"},
+	{Numeric: 152, Alpha2: "CL", Alpha3: "CHL", Name: "Chile"},
+	{Numeric: 156, Alpha2: "CN", Alpha3: "CHN", Name: "China"},
+	{Numeric: 158, Alpha2: "TW", Alpha3: "TWN", Name: "Taiwan, Province of China"},
+	{Numeric: 162, Alpha2: "CX", Alpha3: "CXR", Name: "Christmas Island"},
+	{Numeric: 166, Alpha2: "CC", Alpha3: "CCK", Name: "Cocos (Keeling) Islands"},
+	{Numeric: 170, Alpha2: "CO", Alpha3: "COL", Name: "Colombia"},
+	{Numeric: 174, Alpha2: "KM", Alpha3: "COM", Name: "Comoros"},
+	{Numeric: 175, Alpha2: "YT", Alpha3: "MYT", Name: "Mayotte"},
+	{Numeric: 178, Alpha2: "CG", Alpha3: "COG", Name: "Congo"},
+	{Numeric: 180, Alpha2: "CD", Alpha3: "COD", Name: "Congo, The Democratic Republic of the"},
+	{Numeric: 184, Alpha2: "CK", Alpha3: "COK", Name: "Cook Islands"},
+	{Numeric: 188, Alpha2: "CR", Alpha3: "CRI", Name: "Costa Rica"},
+	{Numeric: 191, Alpha2: "HR", Alpha3: "HRV", Name: "Croatia"},
+	{Numeric: 192, Alpha2: "CU", Alpha3: "CUB", Name: "Cuba"},
+	{Numeric: 196, Alpha2: "CY", Alpha3: "CYP", Name: "Cyprus"},
+	{Numeric: 203, Alpha2: "CZ", Alpha3: "CZE", Name: "Czechia"},
+	{Numeric: 204, Alpha2: "BJ", Alpha3: "BEN", Name: "Benin"},
+	{Numeric: 208, Alpha2: "DK", Alpha3: "DNK", Name: "Denmark"},
+	{Numeric: 212, Alpha2: "DM", Alpha3: "DMA", Name: "Dominica"},
+	{Numeric: 214, Alpha2: "DO", Alpha3: "DOM", Name: "Dominican Republic"},
+	{Numeric: 218, Alpha2: "EC", Alpha3: "ECU", Name: "Ecuador"},
+	{Numeric: 222, Alpha2: "SV", Alpha3: "SLV", Name: "El Salvador"},
+	{Numeric: 226, Alpha2: "GQ", Alpha3: "GNQ", Name: "Equatorial Guinea"},
+	{Numeric: 231, Alpha2: "ET", Alpha3: "ETH", Name: "Ethiopia"},
+	{Numeric: 232, Alpha2: "ER", Alpha3: "ERI", Name: "Eritrea"},
+	{Numeric: 233, Alpha2: "EE", Alpha3: "EST", Name: "Estonia"},
+	{Numeric: 234, Alpha2: "FO", Alpha3: "FRO", Name: "Faroe Islands"},
+	{Numeric: 238, Alpha2: "FK", Alpha3: "FLK", Name: "Falkland Islands (Malvinas)"},
+	{Numeric: 239, Alpha2: "GS", Alpha3: "SGS", Name: "South Georgia and the South Sandwich Islands"},
+	{Numeric: 242, Alpha2: "FJ", Alpha3: "FJI", Name: "Fiji"},
+	{Numeric: 246, Alpha2: "FI", Alpha3: "FIN", Name: "Finland"},
+	{Numeric: 248, Alpha2: "AX", Alpha3: "ALA", Name: "Åland Islands"},
+	{Numeric: 250, Alpha2: "FR", Alpha3: "FRA", Name: "France"},
+	{Numeric: 254, Alpha2: "GF", Alpha3: "GUF", Name: "French Guiana"},
+	{Numeric: 258, Alpha2: "PF", Alpha3: "PYF", Name: "French Polynesia"},
+	{Numeric: 260, Alpha2: "TF", Alpha3: "ATF", Name: "French Southern Territories"},
+	{Numeric: 262, Alpha2: "DJ", Alpha3: "DJI", Name: "Djibouti"},
+	{Numeric: 266, Alpha2: "GA", Alpha3: "GAB", Name: "Gabon"},
+	{Numeric: 268, Alpha2: "GE", Alpha3: "GEO", Name: "Georgia"},
+	{Numeric: 270, Alpha2: "GM", Alpha3: "GMB", Name: "Gambia"},
+	{Numeric: 275, Alpha2: "PS", Alpha3: "PSE", Name: "Palestine, State of"},
+	{Numeric: 276, Alpha2: "DE", Alpha3: "DEU", Name: "Germany"},
+	{Numeric: 288, Alpha2: "GH", Alpha3: "GHA", Name: "Ghana"},
+	{Numeric: 292, Alpha2: "GI", Alpha3: "GIB", Name: "Gibraltar"},
+	{Numeric: 296, Alpha2: "KI", Alpha3: "KIR", Name: "Kiribati"},
+	{Numeric: 300, Alpha2: "GR", Alpha3: "GRC", Name: "Greece"},
+	{Numeric: 304, Alpha2: "GL", Alpha3: "GRL", Name: "Greenland"},
+	{Numeric: 308, Alpha2: "GD", Alpha3: "GRD", Name: "Grenada"},
+	{Numeric: 312, Alpha2: "GP", Alpha3: "GLP", Name: "Guadeloupe"},
+	{Numeric: 316, Alpha2: "GU", Alpha3: "GUM", Name: "Guam"},
+	{Numeric: 320, Alpha2: "GT", Alpha3: "GTM", Name: "Guatemala"},
+	{Numeric: 324, Alpha2: "GN", Alpha3: "GIN", Name: "Guinea"},
+	{Numeric: 328, Alpha2: "GY", Alpha3: "GUY", Name: "Guyana"},
+	{Numeric: 332, Alpha2: "HT", Alpha3: "HTI", Name: "Haiti"},
+	{Numeric: 334, Alpha2: "HM", Alpha3: "HMD", Name: "Heard Island and McDonald Islands"},
+	{Numeric: 336, Alpha2: "VA", Alpha3: "VAT", Name: "Holy See (Vatican City State)"},
+	{Numeric: 340, Alpha2: "HN", Alpha3: "HND", Name: "Honduras"},
+	{Numeric: 344, Alpha2: "HK", Alpha3: "HKG", Name: "Hong Kong"},
+	{Numeric: 348, Alpha2: "HU", Alpha3: "HUN", Name: "Hungary"},
+	{Numeric: 352, Alpha2: "IS", Alpha3: "ISL", Name: "Iceland"},
+	{Numeric: 356, Alpha2: "IN", Alpha3: "IND", Name: "India"},
+	{Numeric: 360, Alpha2: "ID", Alpha3: "IDN", Name: "Indonesia"},
+	{Numeric: 364, Alpha2: "IR", Alpha3: "IRN", Name: "Iran, Islamic Republic of"},
+	{Numeric: 368, Alpha2: "IQ", Alpha3: "IRQ", Name: "Iraq"},
+	{Numeric: 372, Alpha2: "IE", Alpha3: "IRL", Name: "Ireland"},
+	{Numeric: 376, Alpha2: "IL", Alpha3: "ISR", Name: "Israel"},
+	{Numeric: 380, Alpha2: "IT", Alpha3: "ITA", Name: "Italy"},
+	{Numeric: 384, Alpha2: "CI", Alpha3: "CIV", Name: "Côte d'Ivoire"},
+	{Numeric: 388, Alpha2: "JM", Alpha3: "JAM", Name: "Jamaica"},
+	{Numeric: 392, Alpha2: "JP", Alpha3: "JPN", Name: "Japan"},
+	{Numeric: 398, Alpha2: "KZ", Alpha3: "KAZ", Name: "Kazakhstan"},
+	{Numeric: 400, Alpha2: "JO", Alpha3: "JOR", Name: "Jordan"},
+	{Numeric: 404, Alpha2: "KE", Alpha3: "KEN", Name: "Kenya"},
+	{Numeric: 408, Alpha2: "KP", Alpha3: "PRK", Name: "Korea, Democratic People's Republic of"},
+	{Numeric: 410, Alpha2: "KR", Alpha3: "KOR", Name: "Korea, Republic of"},
+	{Numeric: 414, Alpha2: "KW", Alpha3: "KWT", Name: "Kuwait"},
+	{Numeric: 417, Alpha2: "KG", Alpha3: "KGZ", Name: "Kyrgyzstan"},
+	{Numeric: 418, Alpha2: "LA", Alpha3: "LAO", Name: "Lao People's Democratic Republic"},
+	{Numeric: 422, Alpha2: "LB", Alpha3: "LBN", Name: "Lebanon"},
+	{Numeric: 426, Alpha2: "LS", Alpha3: "LSO", Name: "Lesotho"},
+	{Numeric: 428, Alpha2: "LV", Alpha3: "LVA", Name: "Latvia"},
+	{Numeric: 430, Alpha2: "LR", Alpha3: "LBR", Name: "Liberia"},
+	{Numeric: 434, Alpha2: "LY", Alpha3: "LBY", Name: "Libya"},
+	{Numeric: 438, Alpha2: "LI", Alpha3: "LIE", Name: "Liechtenstein"},
+	{Numeric: 440, Alpha2: "LT", Alpha3: "LTU", Name: "Lithuania"},
+	{Numeric: 442, Alpha2: "LU", Alpha3: "LUX", Name: "Luxembourg"},
+	{Numeric: 446, Alpha2: "MO", Alpha3: "MAC", Name: "Macao"},
+	{Numeric: 450, Alpha2: "MG", Alpha3: "MDG", Name: "Madagascar"},
+	{Numeric: 454, Alpha2: "MW", Alpha3: "MWI", Name: "Malawi"},
+	{Numeric: 458, Alpha2: "MY", Alpha3: "MYS", Name: "Malaysia"},
+	{Numeric: 462, Alpha2: "MV", Alpha3: "MDV", Name: "Maldives"},
+	{Numeric: 466, Alpha2: "ML", Alpha3: "MLI", Name: "Mali"},
+	{Numeric: 470, Alpha2: "MT", Alpha3: "MLT", Name: "Malta"},
+	{Numeric: 474, Alpha2: "MQ", Alpha3: "MTQ", Name: "Martinique"},
+	{Numeric: 478, Alpha2: "MR", Alpha3: "MRT", Name: "Mauritania"},
+	{Numeric: 480, Alpha2: "MU", Alpha3: "MUS", Name: "Mauritius"},
+	{Numeric: 484, Alpha2: "MX", Alpha3: "MEX", Name: "Mexico"},
+	{Numeric: 492, Alpha2: "MC", Alpha3: "MCO", Name: "Monaco"},
+	{Numeric: 496, Alpha2: "MN", Alpha3: "MNG", Name: "Mongolia"},
+	{Numeric: 498, Alpha2: "MD", Alpha3: "MDA", Name: "Moldova, Republic of"},
+	{Numeric: 499, Alpha2: "ME", Alpha3: "MNE", Name: "Montenegro"},
+	{Numeric: 500, Alpha2: "MS", Alpha3: "MSR", Name: "Montserrat"},
+	{Numeric: 504, Alpha2: "MA", Alpha3: "MAR", Name: "Morocco"},
+	{Numeric: 508, Alpha2: "MZ", Alpha3: "MOZ", Name: "Mozambique"},
+	{Numeric: 512, Alpha2: "OM", Alpha3: "OMN", Name: "Oman"},
+	{Numeric: 516, Alpha2: "NA", Alpha3: "NAM", Name: "Namibia"},
+	{Numeric: 520, Alpha2: "NR", Alpha3: "NRU", Name: "Nauru"},
+	{Numeric: 524, Alpha2: "NP", Alpha3: "NPL", Name: "Nepal"},
+	{Numeric: 528, Alpha2: "NL", Alpha3: "NLD", Name: "Netherlands"},
+	{Numeric: 531, Alpha2: "CW", Alpha3: "CUW", Name: "Curaçao"},
+	{Numeric: 533, Alpha2: "AW", Alpha3: "ABW", Name: "Aruba"},
+	{Numeric: 534, Alpha2: "SX", Alpha3: "SXM", Name: "Sint Maarten (Dutch part)"},
+	{Numeric: 535, Alpha2: "BQ", Alpha3: "BES", Name: "Bonaire, Sint Eustatius and Saba"},
+	{Numeric: 540, Alpha2: "NC", Alpha3: "NCL", Name: "New Caledonia"},
+	{Numeric: 548, Alpha2: "VU", Alpha3: "VUT", Name: "Vanuatu"},
+	{Numeric: 554, Alpha2: "NZ", Alpha3: "NZL", Name: "New Zealand"},
+	{Numeric: 558, Alpha2: "NI", Alpha3: "NIC", Name: "Nicaragua"},
+	{Numeric: 562, Alpha2: "NE", Alpha3: "NER", Name: "Niger"},
+	{Numeric: 566, Alpha2: "NG", Alpha3: "NGA", Name: "Nigeria"},
+	{Numeric: 570, Alpha2: "NU", Alpha3: "NIU", Name: "Niue"},
+	{Numeric: 574, Alpha2: "NF", Alpha3: "NFK", Name: "Norfolk Island"},
+	{Numeric: 578, Alpha2: "NO", Alpha3: "NOR", Name: "Norway"},
+	{Numeric: 580, Alpha2: "MP", Alpha3: "MNP", Name: "Northern Mariana Islands"},
+	{Numeric: 581, Alpha2: "UM", Alpha3: "UMI", Name: "United States Minor Outlying Islands"},
+	{Numeric: 583, Alpha2: "FM", Alpha3: "FSM", Name: "Micronesia, Federated States of"},
+	{Numeric: 584, Alpha2: "MH", Alpha3: "MHL", Name: "Marshall Islands"},
+	{Numeric: 585, Alpha2: "PW", Alpha3: "PLW", Name: "Palau"},
+	{Numeric: 586, Alpha2: "PK", Alpha3: "PAK", Name: "Pakistan"},
+	{Numeric: 591, Alpha2: "PA", Alpha3: "PAN", Name: "Panama"},
+	{Numeric: 598, Alpha2: "PG", Alpha3: "PNG", Name: "Papua New Guinea"},
+	{Numeric: 600, Alpha2: "PY", Alpha3: "PRY", Name: "Paraguay"},
+	{Numeric: 604, Alpha2: "PE", Alpha3: "PER", Name: "Peru"},
+	{Numeric: 608, Alpha2: "PH", Alpha3: "PHL", Name: "Philippines"},
+	{Numeric: 612, Alpha2: "PN", Alpha3: "PCN", Name: "Pitcairn"},
+	{Numeric: 616, Alpha2: "PL", Alpha3: "POL", Name: "Poland"},
+	{Numeric: 620, Alpha2: "PT", Alpha3: "PRT", Name: "Portugal"},
+	{Numeric: 624, Alpha2: "GW", Alpha3: "GNB", Name: "Guinea-Bissau"},
+	{Numeric: 626, Alpha2: "TL", Alpha3: "TLS", Name: "Timor-Leste"},
+	{Numeric: 630, Alpha2: "PR", Alpha3: "PRI", Name: "Puerto Rico"},
+	{Numeric: 634, Alpha2: "QA", Alpha3: "QAT", Name: "Qatar"},
+	{Numeric: 638, Alpha2: "RE", Alpha3: "REU", Name: "Réunion"},
+	{Numeric: 642, Alpha2: "RO", Alpha3: "ROU", Name: "Romania"},
+	{Numeric: 643, Alpha2: "RU", Alpha3: "RUS", Name: "Russian Federation"},
+	{Numeric: 646, Alpha2: "RW", Alpha3: "RWA", Name: "Rwanda"},
+	{Numeric: 652, Alpha2: "BL", Alpha3: "BLM", Name: "Saint Barthélemy"},
+	{Numeric: 654, Alpha2: "SH", Alpha3: "SHN", Name: "Saint Helena, Ascension and Tristan da Cunha"},
+	{Numeric: 659, Alpha2: "KN", Alpha3: "KNA", Name: "Saint Kitts and Nevis"},
+	{Numeric: 660, Alpha2: "AI", Alpha3: "AIA", Name: "Anguilla"},
+	{Numeric: 662, Alpha2: "LC", Alpha3: "LCA", Name: "Saint Lucia"},
+	{Numeric: 663, Alpha2: "MF", Alpha3: "MAF", Name: "Saint Martin (French part)"},
+	{Numeric: 666, Alpha2: "PM", Alpha3: "SPM", Name: "Saint Pierre and Miquelon"},
+	{Numeric: 670, Alpha2: "VC", Alpha3: "VCT", Name: "Saint Vincent and the Grenadines"},
+	{Numeric: 674, Alpha2: "SM", Alpha3: "SMR", Name: "San Marino"},
+	{Numeric: 678, Alpha2: "ST", Alpha3: "STP", Name: "Sao Tome and Principe"},
+	{Numeric: 682, Alpha2: "SA", Alpha3: "SAU", Name: "Saudi Arabia"},
+	{Numeric: 686, Alpha2: "SN", Alpha3: "SEN", Name: "Senegal"},
+	{Numeric: 688, Alpha2: "RS", Alpha3: "SRB", Name: "Serbia"},
+	{Numeric: 690, Alpha2: "SC", Alpha3: "SYC", Name: "Seychelles"},
+	{Numeric: 694, Alpha2: "SL", Alpha3: "SLE", Name: "Sierra Leone"},
+	{Numeric: 702, Alpha2: "SG", Alpha3: "SGP", Name: "Singapore"},
+	{Numeric: 703, Alpha2: "SK", Alpha3: "SVK", Name: "Slovakia"},
+	{Numeric: 704, Alpha2: "VN", Alpha3: "VNM", Name: "Viet Nam"},
+	{Numeric: 705, Alpha2: "SI", Alpha3: "SVN", Name: "Slovenia"},
+	{Numeric: 706, Alpha2: "SO", Alpha3: "SOM", Name: "Somalia"},
+	{Numeric: 710, Alpha2: "ZA", Alpha3: "ZAF", Name: "South Africa"},
+	{Numeric: 716, Alpha2: "ZW", Alpha3: "ZWE", Name: "Zimbabwe"},
+	{Numeric: 724, Alpha2: "ES", Alpha3: "ESP", Name: "Spain"},
+	{Numeric: 728, Alpha2: "SS", Alpha3: "SSD", Name: "South Sudan"},
+	{Numeric: 729, Alpha2: "SD", Alpha3: "SDN", Name: "Sudan"},
+	{Numeric: 732, Alpha2: "EH", Alpha3: "ESH", Name: "Western Sahara"},
+	{Numeric: 740, Alpha2: "SR", Alpha3: "SUR", Name: "Suriname"},
+	{Numeric: 744, Alpha2: "SJ", Alpha3: "SJM", Name: "Svalbard and Jan Mayen"},
+	{Numeric: 748, Alpha2: "SZ", Alpha3: "SWZ", Name: "Eswatini"},
+	{Numeric: 752, Alpha2: "SE", Alpha3: "SWE", Name: "Sweden"},
+	{Numeric: 756, Alpha2: "CH", Alpha3: "CHE", Name: "Switzerland"},
+	{Numeric: 760, Alpha2: "SY", Alpha3: "SYR", Name: "Syrian Arab Republic"},
+	{Numeric: 762, Alpha2: "TJ", Alpha3: "TJK", Name: "Tajikistan"},
+	{Numeric: 764, Alpha2: "TH", Alpha3: "THA", Name: "Thailand"},
+	{Numeric: 768, Alpha2: "TG", Alpha3: "TGO", Name: "Togo"},
+	{Numeric: 772, Alpha2: "TK", Alpha3: "TKL", Name: "Tokelau"},
+	{Numeric: 776, Alpha2: "TO", Alpha3: "TON", Name: "Tonga"},
+	{Numeric: 780, Alpha2: "TT", Alpha3: "TTO", Name: "Trinidad and Tobago"},
+	{Numeric: 784, Alpha2: "AE", Alpha3: "ARE", Name: "United Arab Emirates"},
+	{Numeric: 788, Alpha2: "TN", Alpha3: "TUN", Name: "Tunisia"},
+	{Numeric: 792, Alpha2: "TR", Alpha3: "TUR", Name: "Türkiye"},
+	{Numeric: 795, Alpha2: "TM", Alpha3: "TKM", Name: "Turkmenistan"},
+	{Numeric: 796, Alpha2: "TC", Alpha3: "TCA", Name: "Turks and Caicos Islands"},
+	{Numeric: 798, Alpha2: "TV", Alpha3: "TUV", Name: "Tuvalu"},
+	{Numeric: 800, Alpha2: "UG", Alpha3: "UGA", Name: "Uganda"},
+	{Numeric: 804, Alpha2: "UA", Alpha3: "UKR", Name: "Ukraine"},
+	{Numeric: 807, Alpha2: "MK", Alpha3: "MKD", Name: "North Macedonia"},
+	{Numeric: 818, Alpha2: "EG", Alpha3: "EGY", Name: "Egypt"},
+	{Numeric: 826, Alpha2: "GB", Alpha3: "GBR", Name: "United Kingdom"},
+	{Numeric: 831, Alpha2: "GG", Alpha3: "GGY", Name: "Guernsey"},
+	{Numeric: 832, Alpha2: "JE", Alpha3: "JEY", Name: "Jersey"},
+	{Numeric: 833, Alpha2: "IM", Alpha3: "IMN", Name: "Isle of Man"},
+	{Numeric: 834, Alpha2: "TZ", Alpha3: "TZA", Name: "Tanzania, United Republic of"},
+	{Numeric: 840, Alpha2: "US", Alpha3: "USA", Name: "United States"},
+	{Numeric: 850, Alpha2: "VI", Alpha3: "VIR", Name: "Virgin Islands, U.S."},
+	{Numeric: 854, Alpha2: "BF", Alpha3: "BFA", Name: "Burkina Faso"},
+	{Numeric: 858, Alpha2: "UY", Alpha3: "URY", Name: "Uruguay"},
+	{Numeric: 860, Alpha2: "UZ", Alpha3: "UZB", Name: "Uzbekistan"},
+	{Numeric: 862, Alpha2: "VE", Alpha3: "VEN", Name: "Venezuela, Bolivarian Republic of"},
+	{Numeric: 876, Alpha2: "WF", Alpha3: "WLF", Name: "Wallis and Futuna"},
+	{Numeric: 882, Alpha2: "WS", Alpha3: "WSM", Name: "Samoa"},
+	{Numeric: 887, Alpha2: "YE", Alpha3: "YEM", Name: "Yemen"},
+	{Numeric: 894, Alpha2: "ZM", Alpha3: "ZMB", Name: "Zambia"},
+}
@@ -0,0 +1,114 @@
+package maxicode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLookupCountry(t *testing.T) {
+	germany := Country{Numeric: 276, Alpha2: "DE", Alpha3: "DEU", Name: "Germany"}
+
+	testCases := []struct {
+		code       string
+		expCountry Country
+		expMode    Mode
+		expErr     error
+	}{
+		{code: "DE", expCountry: germany, expMode: Mode2},
+		{code: "deu", expCountry: germany, expMode: Mode2},
+		{code: "276", expCountry: germany, expMode: Mode2},
+		{code: "USA", expCountry: Country{Numeric: 840, Alpha2: "US", Alpha3: "USA", Name: "United States"}, expMode: Mode2},
+		{code: "SE", expCountry: Country{Numeric: 752, Alpha2: "SE", Alpha3: "SWE", Name: "Sweden"}, expMode: Mode2},
+		{code: "GB", expCountry: Country{Numeric: 826, Alpha2: "GB", Alpha3: "GBR", Name: "United Kingdom"}, expMode: Mode3},
+		{code: "040", expCountry: Country{Numeric: 40, Alpha2: "AT", Alpha3: "AUT", Name: "Austria"}, expMode: Mode2},
+		{code: "HKG", expCountry: Country{Numeric: 344, Alpha2: "HK", Alpha3: "HKG", Name: "Hong Kong"}, expMode: Mode3},
+		{code: "999", expErr: ErrInvalidCountry},
+		{code: "XX", expErr: ErrInvalidCountry},
+		{code: "", expErr: ErrInvalidCountry},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			country, err := LookupCountry(tc.code)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected %v, got %v", tc.expErr, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expCountry, country); diff != "" {
+				t.Errorf("Country is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+
+			if mode := country.Mode(); mode != tc.expMode {
+				t.Errorf("expected %v, got %v", tc.expMode, mode)
+			}
+		})
+	}
+}
+
+func TestUPSShipmentMode(t *testing.T) {
+	testCases := []struct {
+		shipment UPSShipment
+		expMode  Mode
+	}{
+		{shipment: UPSShipment{Country: "US"}, expMode: Mode2},
+		{shipment: UPSShipment{CountryCode: 840}, expMode: Mode2},
+		{shipment: UPSShipment{Country: "DE"}, expMode: Mode3},
+		{shipment: UPSShipment{Country: "CA"}, expMode: Mode3},
+	}
+
+	for _, tc := range testCases {
+		mode, err := tc.shipment.Mode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if mode != tc.expMode {
+			t.Errorf("%+v: expected %v, got %v", tc.shipment, tc.expMode, mode)
+		}
+	}
+}
+
+func TestCountryModeWithSeparators(t *testing.T) {
+	testCases := []struct {
+		code, postcode, expPostcode string
+	}{
+		{code: "SE", postcode: "114 55", expPostcode: "11455"},
+		{code: "PL", postcode: "00-950", expPostcode: "00950"},
+		{code: "PT", postcode: "1000-001", expPostcode: "1000001"},
+		{code: "JP", postcode: "100-0001", expPostcode: "1000001"},
+		{code: "BR", postcode: "01310-100", expPostcode: "01310100"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			country, err := LookupCountry(tc.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if mode := country.Mode(); mode != Mode2 {
+				t.Fatalf("expected %v, got %v", Mode2, mode)
+			}
+
+			grid, err := EncodeStructured(Primary{Postcode: tc.postcode, CountryCode: country.Numeric, ServiceClass: 1}, "PARCEL")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Decode(grid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Mode != Mode2 || result.Primary.Postcode != tc.expPostcode {
+				t.Errorf("expected postcode %q in %v, got %q in %v", tc.expPostcode, Mode2, result.Primary.Postcode, result.Mode)
+			}
+		})
+	}
+}
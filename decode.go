@@ -0,0 +1,489 @@
+package maxicode
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Control functions found in the code set tables next to the data characters.
+const (
+	ctlECI = -(iota + 1)
+	ctlNS
+	ctlPad
+	ctlShiftA
+	ctlShiftB
+	ctlShiftC
+	ctlShiftD
+	ctlShiftE
+	ctl2ShiftA
+	ctl3ShiftA
+	ctlLatchA
+	ctlLatchB
+	ctlLock
+)
+
+// maxiSetChar maps a symbol value in each of the code sets A to E back to
+// its ASCII character, or to one of the ctl* control functions.
+var maxiSetChar = buildSetChars()
+
+func buildSetChars() [5][64]int {
+	var table [5][64]int
+
+	for set := range table {
+		for value := range table[set] {
+			table[set][value] = ctlPad
+		}
+	}
+
+	// Characters belonging to exactly one code set come from Appendix A.
+	for c, set := range maxiCodeSet {
+		if set != 0 {
+			table[set-1][maxiSymbolChar[c]] = c
+		}
+	}
+
+	// Characters that fit into more than one code set.
+	for set := 0; set < 4; set++ {
+		table[set][28] = 28 // FS
+		table[set][29] = 29 // GS
+		table[set][30] = 30 // RS
+	}
+
+	table[0][0] = '\r'
+	table[0][32] = ' '
+	table[0][44] = ','
+	table[0][46] = '.'
+	table[0][47] = '/'
+	table[0][58] = ':'
+
+	table[1][47] = ' '
+	table[1][48] = ','
+	table[1][49] = '.'
+	table[1][50] = '/'
+	table[1][51] = ':'
+
+	table[4][13] = '\r'
+	table[4][32] = 28 // FS
+	table[4][33] = 29 // GS
+	table[4][34] = 30 // RS
+
+	for set := 2; set < 5; set++ {
+		table[set][59] = ' '
+	}
+
+	// Control functions.
+	for set := range table {
+		table[set][27] = ctlECI
+		table[set][31] = ctlNS
+	}
+
+	table[0][59] = ctlShiftB
+	table[0][63] = ctlLatchB
+
+	table[1][56] = ctl2ShiftA
+	table[1][57] = ctl3ShiftA
+	table[1][59] = ctlShiftA
+	table[1][63] = ctlLatchA
+
+	for set := 0; set < 5; set++ {
+		if set >= 2 {
+			table[set][58] = ctlLatchA
+			table[set][63] = ctlLatchB
+		}
+
+		// Shifting into the set you are already in locks it in.
+		for target := 2; target < 5; target++ {
+			if target == set {
+				table[set][60+target-2] = ctlLock
+			} else {
+				table[set][60+target-2] = ctlShiftC - (target - 2)
+			}
+		}
+	}
+
+	return table
+}
+
+// Result is the content recovered from a MaxiCode symbol.
+type Result struct {
+	Mode Mode
+	ECI  int
+	Data string
+
+	// Segments splits Data by the ECI each part is read in, ECI holds the
+	// one of the first segment.
+	Segments []Segment
+
+	// Primary is the primary message of modes 2 and 3. When the secondary
+	// message is a structured carrier message the primary fields are also
+	// put back into Data in their place.
+	Primary *Primary
+
+	// Position and Total place the symbol within a structured append
+	// sequence, both are zero for a standalone symbol.
+	Position int
+	Total    int
+
+	// Corrected is the number of codewords repaired by error correction.
+	Corrected int
+}
+
+// Decode reads the message back out of a symbol grid.
+func Decode(grid *SymbolGrid) (*Result, error) {
+	if grid == nil {
+		return nil, errors.New("symbol grid is nil")
+	}
+
+	codewords := make(maxiCodewords, 144)
+
+	// Copy modules from the symbol grid back into the codewords.
+	for row := 0; row < 33; row++ {
+		for column := 0; column < 30; column++ {
+			g := maxiGrid[(row*30)+column]
+			if g == 0 || !grid.GetModule(row, column) {
+				continue
+			}
+
+			codewords[(g-1)/6] |= 32 >> ((g - 1) % 6)
+		}
+	}
+
+	// Repair the primary message first, it tells how the secondary message is protected.
+	corrected, err := codewords.primaryDataCorrect()
+	if err != nil {
+		return nil, fmt.Errorf("primary message: %w", err)
+	}
+
+	mode := Mode(codewords[0] & 0x0f)
+
+	eccLen := 40
+	if mode == 5 {
+		eccLen = 56
+	}
+
+	for block := 0; block < 2; block++ {
+		n, err := codewords.secondaryDataCorrect(eccLen/2, block)
+		if err != nil {
+			return nil, fmt.Errorf("secondary message: %w", err)
+		}
+
+		corrected += n
+	}
+
+	var message []int
+
+	switch mode {
+	case 2, 3:
+		message = codewords[20:104]
+	case 4, 6:
+		message = append(slices.Clone(codewords[1:10]), codewords[20:104]...)
+	case 5:
+		message = append(slices.Clone(codewords[1:10]), codewords[20:88]...)
+	default:
+		return nil, fmt.Errorf("unsupported %v", mode)
+	}
+
+	result := &Result{
+		Mode:      mode,
+		Corrected: corrected,
+	}
+
+	if err := decodeSecondary(message, result); err != nil {
+		return nil, err
+	}
+
+	if mode == 2 || mode == 3 {
+		postcode, countryCode, serviceClass := codewords.primaryMessage(mode)
+		result.Primary = &Primary{Postcode: postcode, CountryCode: countryCode, ServiceClass: serviceClass}
+	}
+
+	// Only the first symbol of a structured append sequence starts with the header.
+	if p := result.Primary; p != nil && len(result.Segments) > 0 && isSCMHeader(result.Segments[0].Data) {
+		// Put the primary message back in between the header and the rest of the secondary data.
+		first := &result.Segments[0]
+		first.Data = first.Data[:9] + strings.Join([]string{
+			p.Postcode,
+			fmt.Sprintf("%03d", p.CountryCode),
+			fmt.Sprintf("%03d", p.ServiceClass),
+			first.Data[9:],
+		}, GS)
+
+		result.Data = joinSegments(result.Segments)
+	}
+
+	return result, nil
+}
+
+// isSCMHeader reports whether data starts with the header and year of a
+// structured carrier message.
+func isSCMHeader(data string) bool {
+	return len(data) >= 9 && strings.HasPrefix(data, "[)>"+RS+"01"+GS) && isDigits(data[7:9])
+}
+
+func (codewords maxiCodewords) primaryDataCorrect() (int, error) {
+	const (
+		dataLen = 10
+		eccLen  = 10
+	)
+
+	block := make([]byte, dataLen+eccLen)
+	for j := range block {
+		block[j] = byte(codewords[j])
+	}
+
+	n, err := getRsDecoder(eccLen).Decode(block, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	for j := range block {
+		codewords[j] = int(block[j])
+	}
+
+	return n, nil
+}
+
+// secondaryDataCorrect repairs the even (block 0) or odd (block 1) interleaved
+// codewords written by secondaryDataCheckEven and secondaryDataCheckOdd.
+func (codewords maxiCodewords) secondaryDataCorrect(eccLen, block int) (int, error) {
+	dataLen := 68
+	if eccLen == 20 {
+		dataLen = 84
+	}
+
+	data := make([]byte, (dataLen/2)+eccLen)
+	for j := range data {
+		data[j] = byte(codewords[20+(2*j)+block])
+	}
+
+	n, err := getRsDecoder(eccLen).Decode(data, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	for j := range data {
+		codewords[20+(2*j)+block] = int(data[j])
+	}
+
+	return n, nil
+}
+
+func (codewords maxiCodewords) primaryMessage(mode Mode) (postcode string, countryCode, serviceClass int) {
+	countryCode = ((codewords[6] & 0x30) >> 4) | (codewords[7] << 2) | ((codewords[8] & 0x03) << 8)
+	serviceClass = ((codewords[8] & 0x3c) >> 2) | (codewords[9] << 4)
+
+	if mode == 2 {
+		pc := ((codewords[0] & 0x30) >> 4) |
+			(codewords[1] << 2) |
+			(codewords[2] << 8) |
+			(codewords[3] << 14) |
+			(codewords[4] << 20) |
+			((codewords[5] & 0x0f) << 26)
+		postcodeLen := ((codewords[5] & 0x30) >> 4) | ((codewords[6] & 0x0f) << 2)
+
+		return fmt.Sprintf("%0*d", postcodeLen, pc), countryCode, serviceClass
+	}
+
+	pc := make([]byte, 6)
+	for i := range pc {
+		// Each postcode character is split over two codewords.
+		lo := (codewords[5-i] & 0x30) >> 4
+		hi := (codewords[6-i] & 0x0f) << 2
+		pc[i] = ' '
+		if c := maxiSetChar[0][hi|lo]; c >= 0 {
+			pc[i] = byte(c)
+		}
+	}
+
+	return string(pc), countryCode, serviceClass
+}
+
+func decodeSecondary(message []int, result *Result) error {
+	var sb strings.Builder
+
+	// Structured append is a leading PAD followed by the position and total.
+	if len(message) >= 2 && message[0] == 33 {
+		result.Position = (message[1] >> 3) + 1
+		result.Total = (message[1] & 0x07) + 1
+		message = message[2:]
+	}
+
+	var segments []Segment
+	eci := 0
+	set, lastSet := 0, 0
+	shift := 0
+
+	for i := 0; i < len(message); i++ {
+		c := maxiSetChar[set][message[i]]
+
+		switch c {
+		case ctlECI:
+			value, n, err := decodeECI(message[i+1:])
+			if err != nil {
+				return err
+			}
+
+			// A new segment starts whenever the ECI changes after some data.
+			if sb.Len() > 0 {
+				segments = append(segments, Segment{ECI: eci, Data: sb.String()})
+				sb.Reset()
+			}
+
+			eci = value
+
+			i += n
+			continue
+		case ctlNS:
+			if i+5 >= len(message) {
+				return errors.New("numeric shift is truncated")
+			}
+
+			value := (message[i+1] << 24) | (message[i+2] << 18) | (message[i+3] << 12) | (message[i+4] << 6) | message[i+5]
+			fmt.Fprintf(&sb, "%09d", value)
+			i += 5
+		case ctlPad:
+		case ctlShiftA, ctlShiftB, ctlShiftC, ctlShiftD, ctlShiftE:
+			lastSet = set
+			set = ctlShiftA - c
+			shift = 1
+			continue
+		case ctl2ShiftA, ctl3ShiftA:
+			lastSet = set
+			set = 0
+			shift = 2
+			if c == ctl3ShiftA {
+				shift = 3
+			}
+			continue
+		case ctlLatchA, ctlLatchB:
+			set = ctlLatchA - c
+			shift = 0
+			continue
+		case ctlLock:
+			shift = 0
+			continue
+		default:
+			sb.WriteByte(byte(c))
+		}
+
+		if shift > 0 {
+			shift--
+			if shift == 0 {
+				set = lastSet
+			}
+		}
+	}
+
+	if sb.Len() > 0 {
+		segments = append(segments, Segment{ECI: eci, Data: sb.String()})
+	}
+
+	result.Segments = segments
+	result.Data = joinSegments(segments)
+	if len(segments) > 0 {
+		result.ECI = segments[0].ECI
+	}
+
+	return nil
+}
+
+// Reassemble puts the message of a structured append sequence back together.
+// The results may be given in any order, but every symbol must be present.
+func Reassemble(results []Result) (*Result, error) {
+	if len(results) == 0 {
+		return nil, errors.New("no symbols to reassemble")
+	}
+
+	total := results[0].Total
+	if total == 0 {
+		if len(results) > 1 {
+			return nil, errors.New("symbols are not part of a structured append sequence")
+		}
+
+		return &results[0], nil
+	}
+
+	if len(results) != total {
+		return nil, fmt.Errorf("expected %d symbols, got %d", total, len(results))
+	}
+
+	ordered := make([]*Result, total)
+	for i := range results {
+		r := &results[i]
+
+		if r.Total != total || r.Position < 1 || r.Position > total {
+			return nil, fmt.Errorf("symbol %d of %d does not belong to a sequence of %d", r.Position, r.Total, total)
+		}
+
+		if ordered[r.Position-1] != nil {
+			return nil, fmt.Errorf("symbol %d appears more than once", r.Position)
+		}
+
+		ordered[r.Position-1] = r
+	}
+
+	reassembled := &Result{
+		Mode: ordered[0].Mode,
+		ECI:  ordered[0].ECI,
+	}
+
+	// Symbols restate the ECI of a segment that continues from the previous one.
+	for _, r := range ordered {
+		for _, segment := range r.Segments {
+			if n := len(reassembled.Segments); n > 0 && reassembled.Segments[n-1].ECI == segment.ECI {
+				reassembled.Segments[n-1].Data += segment.Data
+				continue
+			}
+
+			reassembled.Segments = append(reassembled.Segments, segment)
+		}
+
+		reassembled.Corrected += r.Corrected
+	}
+
+	reassembled.Data = joinSegments(reassembled.Segments)
+
+	return reassembled, nil
+}
+
+func joinSegments(segments []Segment) string {
+	var sb strings.Builder
+	for _, segment := range segments {
+		sb.WriteString(segment.Data)
+	}
+
+	return sb.String()
+}
+
+// decodeECI reads an ECI assignment number encoded according to table 3
+// and returns it with the number of codewords it occupied.
+func decodeECI(message []int) (int, int, error) {
+	if len(message) == 0 {
+		return 0, 0, errors.New("ECI designator is truncated")
+	}
+
+	var n, value int
+
+	switch c := message[0]; {
+	case c&0x20 == 0:
+		n, value = 1, c
+	case c&0x30 == 0x20:
+		n, value = 2, c&0x0f
+	case c&0x38 == 0x30:
+		n, value = 3, c&0x07
+	default:
+		n, value = 4, c&0x03
+	}
+
+	if len(message) < n {
+		return 0, 0, errors.New("ECI designator is truncated")
+	}
+
+	for _, c := range message[1:n] {
+		value = (value << 6) | c
+	}
+
+	return value, n, nil
+}
@@ -0,0 +1,191 @@
+package maxicode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ingridhq/maxicode/readsolomon"
+)
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		mode      int
+		eci       int
+		inputData string
+		primary   *Primary
+	}{
+		{
+			desc:      "mode 2",
+			mode:      2,
+			inputData: "[)>" + RS + "01" + GS + "96841706672" + GS + "840" + GS + "001" + GS + "1Z12345673" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "N" + GS + "19 SOUTH ST" + GS + "SALTLAKE CITY" + GS + "UT" + RS + EOT,
+			primary:   &Primary{Postcode: "841706672", CountryCode: 840, ServiceClass: 1},
+		},
+		{
+			desc:      "mode 2 with carriage return char",
+			mode:      2,
+			inputData: "[)>" + RS + "01" + GS + "96948509751" + GS + "840" + GS + "988" + GS + "1Z28945956" + GS + "UPSN" + GS + "4X7V81" + RS + "07P" + string(rune(28)) + ":3 0+\"MY&.M8JMZ*CMB$2-4W#2W6UBTXR/PTKAZ-7H\r" + RS + EOT,
+			primary:   &Primary{Postcode: "948509751", CountryCode: 840, ServiceClass: 988},
+		},
+		{
+			desc:      "mode 2 with 5 digit ZIP",
+			mode:      2,
+			inputData: "[)>" + RS + "01" + GS + "9602134" + GS + "840" + GS + "001" + GS + "1Z12345673" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT,
+			primary:   &Primary{Postcode: "02134", CountryCode: 840, ServiceClass: 1},
+		},
+		{
+			desc:      "mode 2 with 1 digit postcode",
+			mode:      2,
+			inputData: "[)>" + RS + "01" + GS + "960" + GS + "840" + GS + "001" + GS + "1Z12345673" + GS + "UPSN" + RS + EOT,
+			primary:   &Primary{Postcode: "0", CountryCode: 840, ServiceClass: 1},
+		},
+		{
+			desc:      "mode 3",
+			mode:      3,
+			inputData: "[)>" + RS + "01" + GS + "09651147" + GS + "276" + GS + "066" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "N" + GS + "5 WALDSTRASSE" + GS + "COLOGNE" + GS + "" + RS + "" + EOT,
+			primary:   &Primary{Postcode: "651147", CountryCode: 276, ServiceClass: 66},
+		},
+		{
+			desc:      "mode 3 with ECI",
+			mode:      3,
+			eci:       3,
+			inputData: "[)>" + RS + "01" + GS + "96SW1A1A" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT,
+			primary:   &Primary{Postcode: "SW1A1A", CountryCode: 826, ServiceClass: 1},
+		},
+		{
+			desc:      "mode 4 with two codeword ECI",
+			mode:      4,
+			eci:       1023,
+			inputData: "TWO CODEWORD ECI",
+		},
+		{
+			desc:      "mode 4 with three codeword ECI",
+			mode:      4,
+			eci:       1024,
+			inputData: "THREE CODEWORD ECI",
+		},
+		{
+			desc:      "mode 4 with four codeword ECI",
+			mode:      4,
+			eci:       999999,
+			inputData: "FOUR CODEWORD ECI",
+		},
+		{
+			desc:      "mode 4 mixed case",
+			mode:      4,
+			inputData: "MaxiCode (19 chars) [mode 4] {set B} ~!@#",
+		},
+		{
+			desc:      "mode 4 numeric compression",
+			mode:      4,
+			inputData: "ORDER 12345678901234567890 SHIPPED 0000000001",
+		},
+		{
+			desc:      "mode 4 code sets C, D and E",
+			mode:      4,
+			inputData: "K\xd6LN \xc4\xd6\xdc\xc5\xc6 stra\xdf\xdfe \xe4\xf6\xfc\xe5\xe6 \x01\x02\x03\x04 \xa9\xae",
+		},
+		{
+			desc:      "mode 5",
+			mode:      5,
+			inputData: "Enhanced error correction: 0123456789",
+		},
+		{
+			desc:      "mode 6",
+			mode:      6,
+			inputData: "READER PROGRAMMING",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			grid, err := Encode(tc.mode, tc.eci, tc.inputData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Decode(grid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := &Result{
+				Mode:     Mode(tc.mode),
+				ECI:      tc.eci,
+				Data:     tc.inputData,
+				Segments: []Segment{{ECI: tc.eci, Data: tc.inputData}},
+				Primary:  tc.primary,
+			}
+
+			if diff := cmp.Diff(expected, result); diff != "" {
+				t.Errorf("Decoded symbol is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// flipCodeword inverts every module that belongs to the given codeword.
+func flipCodeword(grid *SymbolGrid, codeword int) {
+	for row := 0; row < 33; row++ {
+		for column := 0; column < 30; column++ {
+			if g := maxiGrid[(row*30)+column]; g != 0 && (g-1)/6 == codeword {
+				grid.SetModule(row, column, !grid.GetModule(row, column))
+			}
+		}
+	}
+}
+
+func TestDecodeErrorCorrection(t *testing.T) {
+	inputData := "[)>" + RS + "01" + GS + "09651147" + GS + "276" + GS + "066" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "N" + GS + "5 WALDSTRASSE" + GS + "COLOGNE" + GS + "" + RS + "" + EOT
+
+	testCases := []struct {
+		desc      string
+		codewords []int
+		expectErr error
+	}{
+		{
+			desc:      "damaged primary and secondary message",
+			codewords: []int{0, 4, 9, 15, 20, 21, 40, 77, 100, 143},
+		},
+		{
+			desc:      "too many errors in the primary message",
+			codewords: []int{0, 1, 2, 3, 4, 5, 6},
+			expectErr: readsolomon.ErrTooManyErrors,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			grid, err := Encode(3, 0, inputData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, codeword := range tc.codewords {
+				flipCodeword(grid, codeword)
+			}
+
+			result, err := Decode(grid)
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Data != inputData {
+				t.Errorf("expected %q, got %q", inputData, result.Data)
+			}
+
+			if result.Corrected != len(tc.codewords) {
+				t.Errorf("expected %d corrected codewords, got %d", len(tc.codewords), result.Corrected)
+			}
+		})
+	}
+}
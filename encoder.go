@@ -0,0 +1,508 @@
+package maxicode
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Mode is the MaxiCode symbol mode, telling readers how the message is structured.
+type Mode int
+
+const (
+	ModeAuto Mode = 0 // Picked from the message on every call
+	Mode2    Mode = 2 // Structured carrier message with a numeric postcode
+	Mode3    Mode = 3 // Structured carrier message with an alphanumeric postcode
+	Mode4    Mode = 4 // Standard symbol
+	Mode5    Mode = 5 // Full enhanced error correction
+	Mode6    Mode = 6 // Reader programming
+)
+
+func (m Mode) String() string {
+	if m == ModeAuto {
+		return "auto mode"
+	}
+
+	return fmt.Sprintf("mode %d", int(m))
+}
+
+// CodeSetStrategy decides how the secondary message is spread over the code sets.
+type CodeSetStrategy int
+
+const (
+	// CodeSetGreedy looks a few characters ahead when switching code sets.
+	CodeSetGreedy CodeSetStrategy = iota
+	// CodeSetOptimal searches for the shortest codeword stream, fitting more
+	// data into a symbol at the cost of a slower encoder.
+	CodeSetOptimal
+)
+
+// Encoder turns messages into MaxiCode symbols. It holds no state between
+// calls and is safe for concurrent use.
+type Encoder struct {
+	mode       Mode
+	eci        int
+	maxSymbols int
+	strategy   CodeSetStrategy
+	utf8       bool
+	validation Validation
+	warn       func(Warning)
+	primary    *Primary
+}
+
+type Option func(*Encoder)
+
+// Segment is a part of a message with its own Extended Channel
+// Interpretation. An ECI of 0 writes no designator, so the segment stays in
+// the ECI of the segment before it, or in the reader's default for the first.
+type Segment struct {
+	ECI  int
+	Data string
+}
+
+// Primary is the primary message of modes 2 and 3, which readers pick up
+// first to sort parcels.
+type Primary struct {
+	Postcode     string
+	CountryCode  int // ISO 3166 numeric
+	ServiceClass int
+}
+
+// WithPrimary encodes mode 2 and 3 symbols with the given primary message.
+// All the input data then goes into the secondary message as it is, it does
+// not have to be a structured carrier message.
+func WithPrimary(primary Primary) Option {
+	return func(e *Encoder) {
+		e.primary = &primary
+	}
+}
+
+// WithECI prefixes the message with an Extended Channel Interpretation, 0 means none.
+func WithECI(eci int) Option {
+	return func(e *Encoder) {
+		e.eci = eci
+	}
+}
+
+// WithStructuredAppend lets a message that is too long for one symbol be
+// split over a structured append sequence of up to maxSymbols symbols.
+func WithStructuredAppend(maxSymbols int) Option {
+	return func(e *Encoder) {
+		e.maxSymbols = maxSymbols
+	}
+}
+
+// WithUTF8 treats input data as UTF-8 text. Segments without an ECI are
+// transcoded to the ISO-8859 or Windows character sets that hold them, with
+// the ECI designators inserted automatically. Segments with ECI 26 keep their
+// UTF-8 bytes.
+func WithUTF8() Option {
+	return func(e *Encoder) {
+		e.utf8 = true
+	}
+}
+
+// WithCodeSetStrategy chooses how code sets are selected, CodeSetGreedy by default.
+func WithCodeSetStrategy(strategy CodeSetStrategy) Option {
+	return func(e *Encoder) {
+		e.strategy = strategy
+	}
+}
+
+// WithWarnings lets the encoder replace postcode characters that mode 3 cannot
+// carry instead of failing, calling handler for every change it makes to the
+// input.
+func WithWarnings(handler func(Warning)) Option {
+	return func(e *Encoder) {
+		e.warn = handler
+	}
+}
+
+func NewEncoder(mode Mode, opts ...Option) (*Encoder, error) {
+	if mode != ModeAuto && (mode < Mode2 || mode > Mode6) {
+		return nil, newEncodeError(ErrUnsupportedMode, FieldMode, -1, "only modes 2 to 6 supported")
+	}
+
+	e := &Encoder{
+		mode:       mode,
+		maxSymbols: 1,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if err := validateECI(e.eci); err != nil {
+		return nil, err
+	}
+
+	if e.maxSymbols < 1 || e.maxSymbols > 8 {
+		return nil, newEncodeError(ErrInvalidOption, FieldOption, -1, "structured append supports 1 to 8 symbols")
+	}
+
+	if e.strategy != CodeSetGreedy && e.strategy != CodeSetOptimal {
+		return nil, newEncodeError(ErrInvalidOption, FieldOption, -1, "unknown code set strategy")
+	}
+
+	if e.validation < ValidationNone || e.validation > ValidationLenient {
+		return nil, newEncodeError(ErrInvalidOption, FieldOption, -1, "unknown validation")
+	}
+
+	if e.primary != nil && mode != ModeAuto && mode != Mode2 && mode != Mode3 {
+		return nil, newEncodeError(ErrUnsupportedMode, FieldMode, -1, fmt.Sprintf("a primary message needs mode 2 or 3, not %v", mode))
+	}
+
+	return e, nil
+}
+
+// Encode returns the symbols for inputData. That is a single symbol unless
+// structured append is enabled and the message does not fit in one.
+func (e *Encoder) Encode(inputData string) ([]*SymbolGrid, error) {
+	return e.EncodeSegments([]Segment{{ECI: e.eci, Data: inputData}})
+}
+
+// EncodeSegments returns the symbols for a message made of several segments,
+// each switching to its own ECI. For modes 2 and 3 the structured carrier
+// message header must start the first segment.
+func (e *Encoder) EncodeSegments(segments []Segment) ([]*SymbolGrid, error) {
+	e, err := e.resolve(segments, true)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, segments, err := e.prepare(segments, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.encode(primary, segments)
+}
+
+// EncodeBytes returns the symbols for an opaque binary payload. Bytes are
+// never transcoded, values outside of ASCII go through code sets C, D and E.
+// Only modes 4 to 6 carry binary payloads.
+func (e *Encoder) EncodeBytes(data []byte) ([]*SymbolGrid, error) {
+	segments := []Segment{{ECI: e.eci, Data: string(data)}}
+
+	e, err := e.resolve(segments, false)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, segments, err := e.prepare(segments, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.encode(primary, segments)
+}
+
+// Cost is the space a message takes up in a single symbol.
+type Cost struct {
+	// Codewords needed by the message, including ECI designators and the
+	// latch some code sets need before padding.
+	Codewords int
+	// Capacity is the number of codewords the mode has room for.
+	Capacity int
+}
+
+// Fits reports whether the message fits into a single symbol.
+func (c Cost) Fits() bool {
+	return c.Codewords <= c.Capacity
+}
+
+// Cost returns the exact number of codewords the encoder would use for
+// inputData, without laying out a symbol.
+func (e *Encoder) Cost(inputData string) (Cost, error) {
+	return e.cost([]Segment{{ECI: e.eci, Data: inputData}}, true)
+}
+
+// CostBytes is Cost for a payload given to EncodeBytes.
+func (e *Encoder) CostBytes(data []byte) (Cost, error) {
+	return e.cost([]Segment{{ECI: e.eci, Data: string(data)}}, false)
+}
+
+func (e *Encoder) cost(segments []Segment, text bool) (Cost, error) {
+	e, err := e.resolve(segments, text)
+	if err != nil {
+		return Cost{}, err
+	}
+
+	_, segments, err = e.prepare(segments, text)
+	if err != nil {
+		return Cost{}, err
+	}
+
+	_, dataLen, err := secondaryCodewords(e.mode, e.strategy, segments, structuredAppend{})
+	if err != nil {
+		return Cost{}, err
+	}
+
+	return Cost{Codewords: dataLen, Capacity: secondaryCapacity(e.mode)}, nil
+}
+
+// SelectMode returns the mode inputData is encoded in. That is the mode the
+// encoder was created with, unless it is ModeAuto: structured carrier messages
+// then go into mode 2 when their postcode is numeric, and the validation
+// profile allows mode 2 for the country, and mode 3 otherwise,
+// other data into mode 5 when it fits and mode 4 when it does not.
+func (e *Encoder) SelectMode(inputData string) (Mode, error) {
+	e, err := e.resolve([]Segment{{ECI: e.eci, Data: inputData}}, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return e.mode, nil
+}
+
+// resolve returns an encoder for the mode picked for the segments when the
+// encoder is in ModeAuto.
+func (e *Encoder) resolve(segments []Segment, text bool) (*Encoder, error) {
+	if e.mode != ModeAuto {
+		return e, nil
+	}
+
+	resolved := *e
+
+	numeric := func(postcode string, countryCode int) bool {
+		if e.validation.dropsPostcodeSeparators() {
+			postcode = mode2Postcode(postcode, -1, nil)
+		}
+
+		return isDigits(postcode) && len(postcode) <= 9 && (countryCode == 840 || !e.validation.usOnlyMode2())
+	}
+
+	if e.primary != nil {
+		resolved.mode = Mode3
+		if numeric(e.primary.Postcode, e.primary.CountryCode) {
+			resolved.mode = Mode2
+		}
+
+		return &resolved, nil
+	}
+
+	if data := joinSegments(segments); text && strings.HasPrefix(data, "[)>"+RS+"01"+GS) {
+		primary, _, err := splitPrimary(data)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved.mode = Mode3
+		if numeric(primary.Postcode, primary.CountryCode) {
+			resolved.mode = Mode2
+		}
+
+		return &resolved, nil
+	}
+
+	resolved.mode = Mode5
+
+	cost, err := resolved.cost(segments, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cost.Fits() {
+		resolved.mode = Mode4
+	}
+
+	return &resolved, nil
+}
+
+// prepare builds the primary message and returns the segments left for the
+// secondary message. Text is transcoded when the encoder expects UTF-8.
+func (e *Encoder) prepare(segments []Segment, text bool) (maxiCodewords, []Segment, error) {
+	if e.primary != nil {
+		return e.preparePrimary(segments, text)
+	}
+
+	if !text && (e.mode == Mode2 || e.mode == Mode3) {
+		return nil, nil, newEncodeError(ErrUnsupportedMode, FieldMode, -1, fmt.Sprintf("binary payloads are not supported in %v", e.mode))
+	}
+
+	if text && e.utf8 {
+		var err error
+		if segments, err = transcodeSegments(segments); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	segments, err := normalizeSegments(segments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if e.validation == ValidationUPS && (e.mode == Mode2 || e.mode == Mode3) {
+		if err := validateUPS(segments[0].Data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	primary, secondaryData, err := processPrimary(e.mode, segments[0].Data, e.validation, e.warn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments[0].Data = secondaryData
+
+	return primary, segments, nil
+}
+
+// preparePrimary builds the primary message given by WithPrimary, leaving
+// all the segments for the secondary message.
+func (e *Encoder) preparePrimary(segments []Segment, text bool) (maxiCodewords, []Segment, error) {
+	if text && e.utf8 {
+		var err error
+		if segments, err = transcodeSegments(segments); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	segments, err := normalizeSegments(segments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	codewords := make(maxiCodewords, 144)
+	if err := codewords.processPrimaryMessage(e.mode, *e.primary, -1, -1, e.validation, e.warn); err != nil {
+		return nil, nil, err
+	}
+
+	return codewords, segments, nil
+}
+
+func (e *Encoder) encode(primary maxiCodewords, segments []Segment) ([]*SymbolGrid, error) {
+	codewords := slices.Clone(primary)
+
+	err := codewords.processSecondary(e.mode, e.strategy, segments, structuredAppend{})
+	if err == nil {
+		return []*SymbolGrid{codewords.symbolGrid(e.mode)}, nil
+	}
+
+	if !errors.Is(err, ErrTooLong) || e.maxSymbols == 1 {
+		return nil, err
+	}
+
+	chunks, err := e.split(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	grids := make([]*SymbolGrid, len(chunks))
+	for i, chunk := range chunks {
+		codewords := slices.Clone(primary)
+
+		if err := codewords.processSecondary(e.mode, e.strategy, chunk, structuredAppend{i + 1, len(chunks)}); err != nil {
+			return nil, err
+		}
+
+		grids[i] = codewords.symbolGrid(e.mode)
+	}
+
+	return grids, nil
+}
+
+// split fills each symbol of a structured append sequence with as much of the
+// remaining secondary data as it takes. A segment that is split restates its
+// ECI in the next symbol.
+func (e *Encoder) split(segments []Segment) ([][]Segment, error) {
+	var chunks [][]Segment
+
+	length := 0
+	for _, segment := range segments {
+		length += len(segment.Data)
+	}
+
+	capacity := secondaryCapacity(e.mode)
+	needed := 0
+
+	for from := 0; from < length; {
+		n, dataLen := min(length-from, 138), 0
+		for ; n > 0; n-- {
+			var err error
+			if _, dataLen, err = secondaryCodewords(e.mode, e.strategy, sliceSegments(segments, from, from+n), structuredAppend{1, 8}); err != nil {
+				return nil, err
+			}
+
+			if dataLen <= capacity {
+				break
+			}
+		}
+
+		if n == 0 {
+			return nil, tooLongError(dataLen, capacity, "input data is too long")
+		}
+
+		chunks = append(chunks, sliceSegments(segments, from, from+n))
+		needed += dataLen
+		from += n
+	}
+
+	if len(chunks) > e.maxSymbols {
+		return nil, tooLongError(needed, e.maxSymbols*capacity, fmt.Sprintf("input data is too long for %d structured append symbols", e.maxSymbols))
+	}
+
+	return chunks, nil
+}
+
+// normalizeSegments gives every segment the ECI it is read in, drops empty
+// segments and merges neighbours sharing an ECI.
+func normalizeSegments(segments []Segment) ([]Segment, error) {
+	var normalized []Segment
+
+	eci := 0
+	for _, segment := range segments {
+		if err := validateECI(segment.ECI); err != nil {
+			return nil, err
+		}
+
+		if segment.ECI != 0 {
+			eci = segment.ECI
+		}
+
+		if segment.Data == "" {
+			continue
+		}
+
+		if n := len(normalized); n > 0 && normalized[n-1].ECI == eci {
+			normalized[n-1].Data += segment.Data
+			continue
+		}
+
+		normalized = append(normalized, Segment{ECI: eci, Data: segment.Data})
+	}
+
+	if len(normalized) == 0 {
+		return nil, newEncodeError(ErrEmpty, FieldSecondary, 0, "input data is empty")
+	}
+
+	return normalized, nil
+}
+
+// sliceSegments returns the segments covering the bytes from to to of their
+// concatenated data.
+func sliceSegments(segments []Segment, from, to int) []Segment {
+	var sliced []Segment
+
+	offset := 0
+	for _, segment := range segments {
+		start := max(from-offset, 0)
+		end := min(to-offset, len(segment.Data))
+		offset += len(segment.Data)
+
+		if start < end {
+			sliced = append(sliced, Segment{ECI: segment.ECI, Data: segment.Data[start:end]})
+		}
+	}
+
+	return sliced
+}
+
+func validateECI(eci int) error {
+	if eci < 0 || eci > 999999 {
+		return newEncodeError(ErrInvalidECI, FieldECI, -1, fmt.Sprintf("ECI %d is out of range 0 to 999999", eci))
+	}
+
+	return nil
+}
@@ -0,0 +1,552 @@
+package maxicode
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewEncoder(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		mode   Mode
+		opts   []Option
+		expErr string
+	}{
+		{
+			desc: "mode 4 with ECI",
+			mode: Mode4,
+			opts: []Option{WithECI(3)},
+		},
+		{
+			desc:   "mode 1",
+			mode:   1,
+			expErr: "only modes 2 to 6 supported",
+		},
+		{
+			desc:   "negative ECI",
+			mode:   Mode4,
+			opts:   []Option{WithECI(-1)},
+			expErr: "ECI -1 is out of range 0 to 999999",
+		},
+		{
+			desc:   "ECI above 999999",
+			mode:   Mode4,
+			opts:   []Option{WithECI(1000000)},
+			expErr: "ECI 1000000 is out of range 0 to 999999",
+		},
+		{
+			desc:   "too many structured append symbols",
+			mode:   Mode4,
+			opts:   []Option{WithStructuredAppend(9)},
+			expErr: "structured append supports 1 to 8 symbols",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := NewEncoder(tc.mode, tc.opts...)
+			if tc.expErr == "" && err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.expErr != "" && (err == nil || err.Error() != tc.expErr) {
+				t.Fatalf("expected error %q, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestEncoderConcurrentUse(t *testing.T) {
+	encoder, err := NewEncoder(Mode4, WithStructuredAppend(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := []string{
+		"FIRST MESSAGE",
+		"second message",
+		strings.Repeat("LONG MESSAGE SPLIT OVER SEVERAL SYMBOLS ", 5),
+	}
+
+	var wg sync.WaitGroup
+
+	for i := range 30 {
+		wg.Add(1)
+
+		go func(inputData string) {
+			defer wg.Done()
+
+			grids, err := encoder.Encode(inputData)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			var results []Result
+			for _, grid := range grids {
+				result, err := Decode(grid)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				results = append(results, *result)
+			}
+
+			message, err := Reassemble(results)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if message.Data != inputData {
+				t.Errorf("expected %q, got %q", inputData, message.Data)
+			}
+		}(inputs[i%len(inputs)])
+	}
+
+	wg.Wait()
+}
+
+func TestECICodewords(t *testing.T) {
+	testCases := []struct {
+		eci          int
+		expCodewords []int
+	}{
+		{eci: 0, expCodewords: []int{27, 0}},
+		{eci: 31, expCodewords: []int{27, 31}},
+		{eci: 32, expCodewords: []int{27, 0x20, 32}},
+		{eci: 1023, expCodewords: []int{27, 0x2f, 63}},
+		{eci: 1024, expCodewords: []int{27, 0x30, 16, 0}},
+		{eci: 32767, expCodewords: []int{27, 0x37, 63, 63}},
+		{eci: 32768, expCodewords: []int{27, 0x38, 8, 0, 0}},
+		{eci: 999999, expCodewords: []int{27, 0x3b, 52, 8, 63}},
+	}
+
+	for _, tc := range testCases {
+		codewords := eciCodewords(tc.eci)
+		if diff := cmp.Diff(tc.expCodewords, codewords); diff != "" {
+			t.Errorf("ECI %d codewords are not equal to expected, diff (-want, +got):\n%s", tc.eci, diff)
+		}
+
+		value, n, err := decodeECI(codewords[1:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if value != tc.eci || n != len(codewords)-1 {
+			t.Errorf("ECI %d decoded as %d from %d codewords", tc.eci, value, n)
+		}
+	}
+}
+
+func TestEncodeSegments(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		opts        []Option
+		segments    []Segment
+		expSegments []Segment
+		expErr      string
+	}{
+		{
+			desc: "latin-2 and latin-1 address lines",
+			segments: []Segment{
+				{ECI: 4, Data: "UL. \xa3\xd3DZKA 5, \xa3\xd3D\xac" + GS},
+				{ECI: 3, Data: "K\xd6LNER STRA\xdfE 12, K\xd6LN"},
+			},
+			expSegments: []Segment{
+				{ECI: 4, Data: "UL. \xa3\xd3DZKA 5, \xa3\xd3D\xac" + GS},
+				{ECI: 3, Data: "K\xd6LNER STRA\xdfE 12, K\xd6LN"},
+			},
+		},
+		{
+			desc: "default interpretation first",
+			segments: []Segment{
+				{Data: "PLAIN TEXT "},
+				{ECI: 100000, Data: "AFTER A LARGE ECI"},
+			},
+			expSegments: []Segment{
+				{Data: "PLAIN TEXT "},
+				{ECI: 100000, Data: "AFTER A LARGE ECI"},
+			},
+		},
+		{
+			desc: "zero ECI keeps the previous one",
+			segments: []Segment{
+				{ECI: 3, Data: "FIRST "},
+				{Data: "SECOND "},
+				{},
+				{ECI: 3, Data: "THIRD"},
+			},
+			expSegments: []Segment{
+				{ECI: 3, Data: "FIRST SECOND THIRD"},
+			},
+		},
+		{
+			desc: "split over structured append",
+			opts: []Option{WithStructuredAppend(8)},
+			segments: []Segment{
+				{ECI: 4, Data: strings.Repeat("\xa3\xd3D\xac ", 20)},
+				{ECI: 3, Data: strings.Repeat("K\xd6LN ", 30)},
+				{ECI: 2000, Data: "TAIL"},
+			},
+			expSegments: []Segment{
+				{ECI: 4, Data: strings.Repeat("\xa3\xd3D\xac ", 20)},
+				{ECI: 3, Data: strings.Repeat("K\xd6LN ", 30)},
+				{ECI: 2000, Data: "TAIL"},
+			},
+		},
+		{
+			desc:     "invalid ECI",
+			segments: []Segment{{ECI: 1000000, Data: "DATA"}},
+			expErr:   "ECI 1000000 is out of range 0 to 999999",
+		},
+		{
+			desc:     "empty",
+			segments: []Segment{{ECI: 3}},
+			expErr:   "input data is empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			encoder, err := NewEncoder(Mode4, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			grids, err := encoder.EncodeSegments(tc.segments)
+			if tc.expErr != "" {
+				if err == nil || err.Error() != tc.expErr {
+					t.Fatalf("expected error %q, got %v", tc.expErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var results []Result
+			for _, grid := range grids {
+				result, err := Decode(grid)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				results = append(results, *result)
+			}
+
+			message, err := Reassemble(results)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expSegments, message.Segments); diff != "" {
+				t.Errorf("Decoded segments are not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeBytes(t *testing.T) {
+	payload := make([]byte, 40)
+	for i := range payload {
+		payload[i] = byte(i * 37)
+	}
+
+	testCases := []struct {
+		desc   string
+		mode   int
+		data   []byte
+		expErr string
+	}{
+		{
+			desc: "every byte value",
+			mode: 4,
+			data: payload,
+		},
+		{
+			desc: "invalid UTF-8 is kept as is",
+			mode: 5,
+			data: []byte{0xff, 0xfe, 0x00, 0xc3, 0x28},
+		},
+		{
+			desc:   "mode 3",
+			mode:   3,
+			data:   payload,
+			expErr: "binary payloads are not supported in mode 3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			grid, err := EncodeBytes(tc.mode, tc.data)
+			if tc.expErr != "" {
+				if err == nil || err.Error() != tc.expErr {
+					t.Fatalf("expected error %q, got %v", tc.expErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Decode(grid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.data, []byte(result.Data)); diff != "" {
+				t.Errorf("Decoded payload is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncoderCost(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		opts    []Option
+		data    []byte
+		expCost Cost
+		expFits bool
+	}{
+		{
+			desc:    "uppercase",
+			data:    []byte("HELLO"),
+			expCost: Cost{Codewords: 5, Capacity: 93},
+			expFits: true,
+		},
+		{
+			desc:    "ECI and a shift to set B",
+			opts:    []Option{WithECI(1024)},
+			data:    []byte("Hello"),
+			expCost: Cost{Codewords: 10, Capacity: 93},
+			expFits: true,
+		},
+		{
+			desc:    "ending in set C latches back for padding",
+			data:    []byte("\xe0\xe1\xe2\xe3"),
+			expCost: Cost{Codewords: 7, Capacity: 93},
+			expFits: true,
+		},
+		{
+			desc:    "numeric shift",
+			data:    []byte("ABCDEFGHIJ123456789"),
+			expCost: Cost{Codewords: 16, Capacity: 93},
+			expFits: true,
+		},
+		{
+			// The greedy strategy always latches back to set A before padding.
+			desc:    "too long",
+			data:    []byte(strings.Repeat("\x01", 150)),
+			expCost: Cost{Codewords: 153, Capacity: 93},
+		},
+		{
+			desc:    "too long for the optimal strategy",
+			opts:    []Option{WithCodeSetStrategy(CodeSetOptimal)},
+			data:    []byte(strings.Repeat("\x01", 150)),
+			expCost: Cost{Codewords: 152, Capacity: 93},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			encoder, err := NewEncoder(Mode4, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cost, err := encoder.CostBytes(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expCost, cost); diff != "" {
+				t.Errorf("Cost is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+
+			if cost.Fits() != tc.expFits {
+				t.Errorf("expected fits to be %t", tc.expFits)
+			}
+
+			_, err = encoder.EncodeBytes(tc.data)
+			if tc.expFits != (err == nil) {
+				t.Errorf("cost does not agree with the encoder: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodeAuto(t *testing.T) {
+	header := "[)>" + RS + "01" + GS + "96"
+	rest := GS + "001" + GS + "1Z12345673" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT
+
+	testCases := []struct {
+		desc      string
+		inputData string
+		expMode   Mode
+		expErr    bool
+	}{
+		{
+			desc:      "numeric US postcode",
+			inputData: header + "90210" + GS + "840" + rest,
+			expMode:   Mode2,
+		},
+		{
+			desc:      "alphanumeric postcode",
+			inputData: header + "SW1A1A" + GS + "826" + rest,
+			expMode:   Mode3,
+		},
+		{
+			desc:      "numeric postcode outside the US",
+			inputData: header + "651147" + GS + "276" + rest,
+			expMode:   Mode3,
+		},
+		{
+			desc:      "fits mode 5",
+			inputData: strings.Repeat("A", 77),
+			expMode:   Mode5,
+		},
+		{
+			desc:      "too long for mode 5",
+			inputData: strings.Repeat("A", 78),
+			expMode:   Mode4,
+		},
+		{
+			desc:      "broken structured carrier message",
+			inputData: header + "90210" + GS + "840" + GS + "001",
+			expErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			grid, mode, err := EncodeAuto(0, tc.inputData)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if mode != tc.expMode {
+				t.Errorf("expected %v, got %v", tc.expMode, mode)
+			}
+
+			result, err := Decode(grid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Mode != tc.expMode || result.Data != tc.inputData {
+				t.Errorf("expected %v with %q, got %v with %q", tc.expMode, tc.inputData, result.Mode, result.Data)
+			}
+		})
+	}
+}
+
+func TestEncodeStructured(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		primary   Primary
+		secondary string
+		expMode   Mode
+		expResult *Primary
+		expErr    error
+	}{
+		{
+			desc:      "numeric US postcode",
+			primary:   Primary{Postcode: "90210", CountryCode: 840, ServiceClass: 3},
+			secondary: "ROUTE 12 BIN 4",
+			expMode:   Mode2,
+			expResult: &Primary{Postcode: "90210", CountryCode: 840, ServiceClass: 3},
+		},
+		{
+			desc:      "alphanumeric postcode",
+			primary:   Primary{Postcode: "w1a 0ax", CountryCode: 826, ServiceClass: 1},
+			secondary: "DEPOT 7",
+			expMode:   Mode3,
+			expResult: &Primary{Postcode: "W1A0AX", CountryCode: 826, ServiceClass: 1},
+		},
+		{
+			desc:      "numeric postcode outside the US",
+			primary:   Primary{Postcode: "11351", CountryCode: 752, ServiceClass: 10},
+			secondary: "[)>" + RS + "06" + GS + "1JUN0001" + RS + EOT,
+			expMode:   Mode2,
+			expResult: &Primary{Postcode: "11351", CountryCode: 752, ServiceClass: 10},
+		},
+		{
+			desc:      "unknown country",
+			primary:   Primary{Postcode: "11351", CountryCode: 999, ServiceClass: 10},
+			secondary: "DEPOT 7",
+			expErr:    ErrInvalidCountry,
+		},
+		{
+			desc:      "service class",
+			primary:   Primary{Postcode: "11351", CountryCode: 752, ServiceClass: 1000},
+			secondary: "DEPOT 7",
+			expErr:    ErrInvalidServiceClass,
+		},
+		{
+			desc:    "empty secondary",
+			primary: Primary{Postcode: "11351", CountryCode: 752, ServiceClass: 10},
+			expErr:  ErrEmpty,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			grid, err := EncodeStructured(tc.primary, tc.secondary)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected %v, got %v", tc.expErr, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			result, err := Decode(grid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := &Result{
+				Mode:     tc.expMode,
+				Data:     tc.secondary,
+				Segments: []Segment{{Data: tc.secondary}},
+				Primary:  tc.expResult,
+			}
+
+			if diff := cmp.Diff(expected, result); diff != "" {
+				t.Errorf("Decoded symbol is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := NewEncoder(Mode4, WithPrimary(Primary{})); !errors.Is(err, ErrUnsupportedMode) {
+		t.Errorf("expected %v for a primary message in mode 4, got %v", ErrUnsupportedMode, err)
+	}
+
+	encoder, err := NewEncoder(Mode3, WithPrimary(Primary{Postcode: "K1A0B1", CountryCode: 124, ServiceClass: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := encoder.EncodeBytes([]byte{0x00, 0xff, 0x80}); err != nil {
+		t.Errorf("expected a binary secondary message to be encoded, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package maxicode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Envelope is a format envelope of an ANSI MH10.8.3 / ISO 15434 message, as
+// found after the format 01 envelope of a structured carrier message.
+type Envelope struct {
+	// Format is the 2 digit format code, such as "05" for GS1 application
+	// identifiers, "06" for ASC data identifiers or "07" for free form data.
+	Format string
+
+	// Elements are the data elements of formats 05 and 06.
+	Elements []DataElement
+
+	// Data is the content of the other formats, without the format code.
+	Data string
+}
+
+// DataElement is a value tagged with a GS1 application identifier in format
+// 05, or with an ASC MH10 data identifier in format 06.
+type DataElement struct {
+	ID    string
+	Value string
+}
+
+// gs1AILengths is the length of GS1 application identifiers by their first
+// two digits.
+var gs1AILengths = map[string]int{
+	"00": 2, "01": 2, "02": 2, "03": 2, "04": 2,
+	"10": 2, "11": 2, "12": 2, "13": 2, "15": 2, "16": 2, "17": 2,
+	"20": 2, "21": 2, "22": 2, "23": 3, "24": 3, "25": 3,
+	"30": 2, "31": 4, "32": 4, "33": 4, "34": 4, "35": 4, "36": 4, "37": 2, "39": 4,
+	"40": 3, "41": 3, "42": 3, "43": 4,
+	"70": 4, "71": 3, "72": 4,
+	"80": 4, "81": 4, "82": 4,
+	"90": 2, "91": 2, "92": 2, "93": 2, "94": 2, "95": 2, "96": 2, "97": 2, "98": 2, "99": 2,
+}
+
+// splitGS1 splits a format 05 data element into its application identifier
+// and value.
+func splitGS1(element string) (DataElement, bool) {
+	if len(element) < 2 {
+		return DataElement{}, false
+	}
+
+	n, ok := gs1AILengths[element[:2]]
+	if !ok || len(element) <= n || !isDigits(element[:n]) {
+		return DataElement{}, false
+	}
+
+	return DataElement{ID: element[:n], Value: element[n:]}, true
+}
+
+// splitASC splits a format 06 data element into its data identifier, up to 3
+// digits followed by a letter, and value.
+func splitASC(element string) (DataElement, bool) {
+	n := 0
+	for n < len(element) && n < 3 && element[n] >= '0' && element[n] <= '9' {
+		n++
+	}
+
+	if n >= len(element)-1 || element[n] < 'A' || element[n] > 'Z' {
+		return DataElement{}, false
+	}
+
+	return DataElement{ID: element[:n+1], Value: element[n+1:]}, true
+}
+
+// parseEnvelopes reads the format envelopes in data, each closed by an RS.
+// Offsets in errors count from offset, where data starts in the message.
+func parseEnvelopes(data string, offset int) ([]Envelope, error) {
+	var envelopes []Envelope
+
+	for data != "" {
+		end := strings.Index(data, RS)
+		if end == -1 {
+			return nil, newEncodeError(ErrInvalidHeader, FieldEnvelope, offset+len(data), "format envelope should end with RS")
+		}
+
+		content := data[:end]
+		if len(content) < 2 || !isDigits(content[:2]) {
+			return nil, newEncodeError(ErrInvalidHeader, FieldEnvelope, offset, "format envelope should start with a 2 digit format")
+		}
+
+		envelope := Envelope{Format: content[:2]}
+
+		switch envelope.Format {
+		case "05", "06":
+			if len(content) < 4 || content[2:3] != GS {
+				return nil, newEncodeError(ErrInvalidHeader, FieldEnvelope, offset+2, fmt.Sprintf("format %s envelope should have data elements following a GS", envelope.Format))
+			}
+
+			split, kind := splitGS1, "GS1 application identifier"
+			if envelope.Format == "06" {
+				split, kind = splitASC, "data identifier"
+			}
+
+			at := offset + 3
+			for _, element := range strings.Split(content[3:], GS) {
+				e, ok := split(element)
+				if !ok {
+					return nil, newEncodeError(ErrInvalidField, FieldEnvelope, at, fmt.Sprintf("data element %q does not start with a %s", element, kind))
+				}
+
+				envelope.Elements = append(envelope.Elements, e)
+				at += len(element) + 1
+			}
+		default:
+			envelope.Data = content[2:]
+		}
+
+		envelopes = append(envelopes, envelope)
+		data = data[end+1:]
+		offset += end + 1
+	}
+
+	return envelopes, nil
+}
+
+// String returns the envelope as it appears in a message, closed by an RS.
+func (e Envelope) String() string {
+	if e.Format != "05" && e.Format != "06" {
+		return e.Format + e.Data + RS
+	}
+
+	elements := make([]string, len(e.Elements))
+	for i, element := range e.Elements {
+		elements[i] = element.ID + element.Value
+	}
+
+	return e.Format + GS + strings.Join(elements, GS) + RS
+}
+
+func (e Envelope) validate() error {
+	if len(e.Format) != 2 || !isDigits(e.Format) || e.Format == "01" {
+		return newEncodeError(ErrInvalidField, FieldEnvelope, -1, fmt.Sprintf("format %q is not a 2 digit format following format 01", e.Format))
+	}
+
+	if e.Format != "05" && e.Format != "06" {
+		if strings.ContainsAny(e.Data, RS+EOT) {
+			return newEncodeError(ErrInvalidField, FieldEnvelope, -1, fmt.Sprintf("format %s data contains a separator character", e.Format))
+		}
+
+		return nil
+	}
+
+	if len(e.Elements) == 0 {
+		return newEncodeError(ErrInvalidField, FieldEnvelope, -1, fmt.Sprintf("format %s envelope has no data elements", e.Format))
+	}
+
+	split := splitGS1
+	if e.Format == "06" {
+		split = splitASC
+	}
+
+	for _, element := range e.Elements {
+		if parsed, ok := split(element.ID + element.Value); !ok || parsed.ID != element.ID {
+			return newEncodeError(ErrInvalidField, FieldEnvelope, -1, fmt.Sprintf("%q is not a valid identifier for format %s", element.ID, e.Format))
+		}
+
+		if strings.ContainsAny(element.Value, RS+GS+EOT) {
+			return newEncodeError(ErrInvalidField, FieldEnvelope, -1, fmt.Sprintf("data element %s contains a separator character", element.ID))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package maxicode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSCMEnvelopes(t *testing.T) {
+	envelope01 := "[)>" + RS + "01" + GS + "96SW1A1A" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + RS
+
+	testCases := []struct {
+		desc         string
+		trailing     string
+		expEnvelopes []Envelope
+		expErr       error
+		expOffset    int
+	}{
+		{
+			desc:     "all formats",
+			trailing: "05" + GS + "00123456789012345675" + GS + "3103000250" + RS + "06" + GS + "1JUN123456789" + GS + "25SUN123" + GS + "Q1" + RS + "07" + "HANDLE WITH CARE" + RS + "12" + "OTHER" + RS,
+			expEnvelopes: []Envelope{
+				{Format: "05", Elements: []DataElement{{ID: "00", Value: "123456789012345675"}, {ID: "3103", Value: "000250"}}},
+				{Format: "06", Elements: []DataElement{{ID: "1J", Value: "UN123456789"}, {ID: "25S", Value: "UN123"}, {ID: "Q", Value: "1"}}},
+				{Format: "07", Data: "HANDLE WITH CARE"},
+				{Format: "12", Data: "OTHER"},
+			},
+		},
+		{
+			desc:      "missing RS",
+			trailing:  "07" + "HANDLE WITH CARE",
+			expErr:    ErrInvalidHeader,
+			expOffset: 65,
+		},
+		{
+			desc:      "format",
+			trailing:  "X7" + "HANDLE WITH CARE" + RS,
+			expErr:    ErrInvalidHeader,
+			expOffset: 47,
+		},
+		{
+			desc:      "missing GS",
+			trailing:  "06" + "1JUN123456789" + RS,
+			expErr:    ErrInvalidHeader,
+			expOffset: 49,
+		},
+		{
+			desc:      "unknown application identifier",
+			trailing:  "05" + GS + "00123456789012345675" + GS + "5012" + RS,
+			expErr:    ErrInvalidField,
+			expOffset: 71,
+		},
+		{
+			desc:      "data identifier",
+			trailing:  "06" + GS + "1JUN123456789" + GS + "1234S" + RS,
+			expErr:    ErrInvalidField,
+			expOffset: 64,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			scm, err := ParseSCM(envelope01 + tc.trailing + EOT)
+			if tc.expErr != nil {
+				var encodeErr *EncodeError
+				if !errors.Is(err, tc.expErr) || !errors.As(err, &encodeErr) {
+					t.Fatalf("expected %v, got %v", tc.expErr, err)
+				}
+
+				if encodeErr.Field != FieldEnvelope || encodeErr.Offset != tc.expOffset {
+					t.Errorf("expected %s at offset %d, got %s at offset %d", FieldEnvelope, tc.expOffset, encodeErr.Field, encodeErr.Offset)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expEnvelopes, scm.Envelopes); diff != "" {
+				t.Errorf("Envelopes are not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUPSShipmentMessageEnvelopes(t *testing.T) {
+	shipment := UPSShipment{
+		Postcode:       "SW1A1A",
+		CountryCode:    826,
+		ServiceClass:   1,
+		TrackingNumber: "1Z12345677",
+		SCAC:           "UPSN",
+		ShipperNumber:  "1X2X3X",
+	}
+
+	envelopes := []Envelope{
+		{Format: "06", Elements: []DataElement{{ID: "1J", Value: "UN123456789"}, {ID: "Q", Value: "2"}}},
+		{Format: "07", Data: "FRAGILE"},
+	}
+
+	message, err := shipment.Message(envelopes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grid, err := Encode(3, 0, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Decode(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scm, err := result.SCM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(envelopes, scm.Envelopes); diff != "" {
+		t.Errorf("Envelopes are not equal to expected, diff (-want, +got):\n%s", diff)
+	}
+
+	invalid := []Envelope{
+		{Format: "01", Data: "96"},
+		{Format: "05", Elements: []DataElement{{ID: "3", Value: "1"}}},
+		{Format: "06", Elements: []DataElement{{ID: "1J", Value: "UN1" + GS}}},
+		{Format: "06"},
+		{Format: "07", Data: "FRAGILE" + RS},
+	}
+
+	for _, envelope := range invalid {
+		_, err := shipment.Message(envelope)
+
+		var encodeErr *EncodeError
+		if !errors.Is(err, ErrInvalidField) || !errors.As(err, &encodeErr) || encodeErr.Field != FieldEnvelope {
+			t.Errorf("expected an envelope error for %q, got %v", envelope, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package maxicode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// EPSOptions change how WriteEPS draws a symbol.
+type EPSOptions struct {
+	// ModuleSize is the distance between the centres of neighbouring modules
+	// in millimetres, 0.88 by default. The whole symbol scales with it.
+	ModuleSize float64
+}
+
+// WriteEPS writes the symbol as an Encapsulated PostScript file in the same
+// geometry as Draw, with the bullseye rings as arcs and a procedure drawing
+// each module.
+func (s *SymbolGrid) WriteEPS(w io.Writer, opts EPSOptions) error {
+	moduleSize := opts.ModuleSize
+	if moduleSize == 0 {
+		moduleSize = modulePitch
+	}
+
+	if moduleSize < 0 || math.IsNaN(moduleSize) || math.IsInf(moduleSize, 0) {
+		return errors.New("module size must be positive")
+	}
+
+	scale := ptPerMM * moduleSize / modulePitch
+	width, height := symbolWidth*scale, symbolHeight*scale
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "%!PS-Adobe-3.0 EPSF-3.0")
+	fmt.Fprintln(bw, "%%Creator: github.com/ingridhq/maxicode")
+	fmt.Fprintf(bw, "%%%%BoundingBox: 0 0 %d %d\n", int(math.Ceil(width)), int(math.Ceil(height)))
+	fmt.Fprintf(bw, "%%%%HiResBoundingBox: 0 0 %s %s\n", decimal(width), decimal(height))
+	bw.WriteString("%%EndComments\n")
+
+	// Draw in millimetres from the top left corner, like Draw does.
+	fmt.Fprintln(bw, "gsave")
+	fmt.Fprintf(bw, "0 %s translate %s %s scale\n", decimal(height), decimal(scale), decimal(-scale))
+	fmt.Fprintln(bw, "0 setgray")
+
+	fmt.Fprintf(bw, "%s setlinewidth\n", decimal(bullseyeLineWidth))
+	for _, r := range bullseyeRadii {
+		fmt.Fprintf(bw, "newpath %s %s %s 0 360 arc closepath stroke\n", decimal(bullseyeX), decimal(bullseyeY), decimal(r))
+	}
+
+	// h fills the module whose top corner is at x y.
+	fmt.Fprint(bw, "/h { moveto ")
+	hex := hexagon(0, 0)
+	for i := 1; i < len(hex); i++ {
+		fmt.Fprintf(bw, "%s %s rlineto ", decimal(hex[i].x-hex[i-1].x), decimal(hex[i].y-hex[i-1].y))
+	}
+	fmt.Fprintln(bw, "closepath fill } bind def")
+
+	for row := range 33 {
+		for column := range 30 {
+			if s.GetModule(row, column) {
+				top := hexagon(row, column)[0]
+				fmt.Fprintf(bw, "%s %s h\n", decimal(top.x), decimal(top.y))
+			}
+		}
+	}
+
+	fmt.Fprintln(bw, "grestore")
+	bw.WriteString("%%EOF\n")
+
+	return bw.Flush()
+}
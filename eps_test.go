@@ -0,0 +1,57 @@
+package maxicode
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteEPS(t *testing.T) {
+	grid := testGrid(t)
+
+	modules := countModules(grid)
+
+	testCases := []struct {
+		desc           string
+		opts           EPSOptions
+		expBoundingBox string
+	}{
+		{desc: "default", expBoundingBox: "%%BoundingBox: 0 0 80 76\n%%HiResBoundingBox: 0 0 79.3701 75.9685\n"},
+		{desc: "1 mm modules", opts: EPSOptions{ModuleSize: 1}, expBoundingBox: "%%BoundingBox: 0 0 91 87\n%%HiResBoundingBox: 0 0 90.1933 86.3278\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := grid.WriteEPS(&buf, tc.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			eps := buf.String()
+			if !strings.HasPrefix(eps, "%!PS-Adobe-3.0 EPSF-3.0\n") || !strings.HasSuffix(eps, "%%EOF\n") {
+				t.Fatal("missing EPS header or trailer")
+			}
+
+			if !strings.Contains(eps, tc.expBoundingBox) {
+				t.Errorf("expected bounding box\n%s", tc.expBoundingBox)
+			}
+
+			if n := strings.Count(eps, " 0 360 arc "); n != 3 {
+				t.Errorf("expected 3 bullseye rings, got %d", n)
+			}
+
+			if !strings.Contains(eps, "/h { moveto 0.38 0.22 rlineto 0 0.44 rlineto -0.38 0.22 rlineto -0.38 -0.22 rlineto 0 -0.44 rlineto closepath fill } bind def\n") {
+				t.Error("unexpected module procedure")
+			}
+
+			if n := strings.Count(eps, " h\n"); n != modules {
+				t.Errorf("expected %d hexagons, got %d", modules, n)
+			}
+		})
+	}
+
+	if err := grid.WriteEPS(io.Discard, EPSOptions{ModuleSize: -1}); err == nil {
+		t.Error("expected an error for a negative module size")
+	}
+}
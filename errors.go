@@ -0,0 +1,134 @@
+package maxicode
+
+import (
+	"errors"
+	"strings"
+)
+
+// Sentinel errors returned by the encoder, wrapped in an *EncodeError that
+// tells where the problem is. Test for them with errors.Is.
+var (
+	ErrUnsupportedMode     = errors.New("unsupported mode")
+	ErrInvalidHeader       = errors.New("invalid structured carrier message")
+	ErrInvalidPostcode     = errors.New("invalid postcode")
+	ErrInvalidCountry      = errors.New("invalid country code")
+	ErrInvalidServiceClass = errors.New("invalid service class")
+	ErrInvalidField        = errors.New("invalid field")
+	ErrInvalidCheckDigit   = errors.New("invalid check digit")
+	ErrInvalidECI          = errors.New("invalid ECI")
+	ErrInvalidOption       = errors.New("invalid option")
+	ErrInvalidUTF8         = errors.New("invalid UTF-8")
+	ErrUnrepresentable     = errors.New("character cannot be represented")
+	ErrEmpty               = errors.New("input data is empty")
+	ErrTooLong             = errors.New("input data is too long")
+)
+
+// Field names the part of the message an EncodeError is about.
+type Field string
+
+const (
+	FieldMode         Field = "mode"
+	FieldHeader       Field = "header"
+	FieldPostcode     Field = "postcode"
+	FieldCountry      Field = "country code"
+	FieldServiceClass Field = "service class"
+	FieldECI          Field = "ECI"
+	FieldOption       Field = "option"
+	FieldSecondary    Field = "secondary message"
+
+	FieldTrackingNumber    Field = "tracking number"
+	FieldSCAC              Field = "SCAC"
+	FieldShipperNumber     Field = "shipper number"
+	FieldPickupDay         Field = "pickup day"
+	FieldShipmentID        Field = "shipment ID"
+	FieldPackage           Field = "package n/x"
+	FieldWeight            Field = "weight"
+	FieldAddressValidation Field = "address validation"
+	FieldShipToAddress     Field = "ship to address"
+	FieldShipToCity        Field = "ship to city"
+	FieldShipToState       Field = "ship to state"
+	FieldEnvelope          Field = "format envelope"
+)
+
+// EncodeError describes why a message could not be encoded. Use errors.As to
+// get at it.
+type EncodeError struct {
+	// Err is one of the sentinel errors.
+	Err   error
+	Field Field
+
+	// Offset is the byte offset of the problem in the input data, or -1 when
+	// it is not tied to a position.
+	Offset int
+
+	// Needed and Available are the codewords the message takes up and the
+	// room there is for them, set for ErrTooLong.
+	Needed    int
+	Available int
+
+	msg string
+}
+
+func (e *EncodeError) Error() string {
+	return e.msg
+}
+
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
+
+func newEncodeError(err error, field Field, offset int, msg string) *EncodeError {
+	return &EncodeError{
+		Err:    err,
+		Field:  field,
+		Offset: offset,
+		msg:    msg,
+	}
+}
+
+func tooLongError(needed, available int, msg string) *EncodeError {
+	return &EncodeError{
+		Err:       ErrTooLong,
+		Field:     FieldSecondary,
+		Offset:    -1,
+		Needed:    needed,
+		Available: available,
+		msg:       msg,
+	}
+}
+
+// Warning is a change the encoder made to the input to fit it into a symbol.
+type Warning struct {
+	Field Field
+	// Offset is the byte offset of the change in the input data.
+	Offset  int
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
+
+// ValidationError lists every problem a validation profile found in a
+// message. errors.Is matches the sentinel of any of them.
+type ValidationError struct {
+	Problems []*EncodeError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		msgs[i] = problem.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, problem := range e.Problems {
+		errs[i] = problem
+	}
+
+	return errs
+}
@@ -0,0 +1,143 @@
+package maxicode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncodeErrors(t *testing.T) {
+	header := "[)>" + RS + "01" + GS + "96"
+
+	testCases := []struct {
+		desc      string
+		mode      Mode
+		opts      []Option
+		inputData string
+		expErr    *EncodeError
+	}{
+		{
+			desc:      "mode 1",
+			mode:      1,
+			inputData: "DATA",
+			expErr:    &EncodeError{Err: ErrUnsupportedMode, Field: FieldMode, Offset: -1},
+		},
+		{
+			desc:      "ECI out of range",
+			mode:      Mode4,
+			opts:      []Option{WithECI(1000000)},
+			inputData: "DATA",
+			expErr:    &EncodeError{Err: ErrInvalidECI, Field: FieldECI, Offset: -1},
+		},
+		{
+			desc:      "structured append out of range",
+			mode:      Mode4,
+			opts:      []Option{WithStructuredAppend(9)},
+			inputData: "DATA",
+			expErr:    &EncodeError{Err: ErrInvalidOption, Field: FieldOption, Offset: -1},
+		},
+		{
+			desc:      "unknown code set strategy",
+			mode:      Mode4,
+			opts:      []Option{WithCodeSetStrategy(CodeSetStrategy(7))},
+			inputData: "DATA",
+			expErr:    &EncodeError{Err: ErrInvalidOption, Field: FieldOption, Offset: -1},
+		},
+		{
+			desc:      "unknown validation",
+			mode:      Mode3,
+			opts:      []Option{WithValidation(Validation(7))},
+			inputData: "DATA",
+			expErr:    &EncodeError{Err: ErrInvalidOption, Field: FieldOption, Offset: -1},
+		},
+		{
+			desc:      "missing header",
+			mode:      Mode3,
+			inputData: "SW1A1A" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT,
+			expErr:    &EncodeError{Err: ErrInvalidHeader, Field: FieldHeader, Offset: 0},
+		},
+		{
+			desc:      "postcode length",
+			mode:      Mode2,
+			inputData: header + "8417066720" + GS + "840" + GS + "001" + GS + "1Z12345673" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT,
+			expErr:    &EncodeError{Err: ErrInvalidPostcode, Field: FieldPostcode, Offset: 9},
+		},
+		{
+			desc:      "country code",
+			mode:      Mode3,
+			inputData: header + "SW1A1A" + GS + "GBR" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT,
+			expErr:    &EncodeError{Err: ErrInvalidCountry, Field: FieldCountry, Offset: 16},
+		},
+		{
+			desc:      "unknown country code",
+			mode:      Mode3,
+			opts:      []Option{WithValidation(ValidationISO)},
+			inputData: header + "SW1A1A" + GS + "999" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT,
+			expErr:    &EncodeError{Err: ErrInvalidCountry, Field: FieldCountry, Offset: 16},
+		},
+		{
+			desc:      "service class",
+			mode:      Mode3,
+			inputData: header + "SW1A1A" + GS + "826" + GS + "1" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT,
+			expErr:    &EncodeError{Err: ErrInvalidServiceClass, Field: FieldServiceClass, Offset: 20},
+		},
+		{
+			desc:      "unrepresentable character",
+			mode:      Mode4,
+			opts:      []Option{WithUTF8()},
+			inputData: "TOKYO 東京",
+			expErr:    &EncodeError{Err: ErrUnrepresentable, Field: FieldSecondary, Offset: 6},
+		},
+		{
+			desc:   "empty",
+			mode:   Mode4,
+			expErr: &EncodeError{Err: ErrEmpty, Field: FieldSecondary, Offset: 0},
+		},
+		{
+			desc:      "too long",
+			mode:      Mode5,
+			inputData: strings.Repeat("ABCDEFGHIJ", 8),
+			expErr:    &EncodeError{Err: ErrTooLong, Field: FieldSecondary, Offset: -1, Needed: 80, Available: 77},
+		},
+		{
+			desc:      "too long for structured append",
+			mode:      Mode4,
+			opts:      []Option{WithStructuredAppend(2)},
+			inputData: strings.Repeat("ABCDEFGHIJ", 20),
+			expErr:    &EncodeError{Err: ErrTooLong, Field: FieldSecondary, Offset: -1, Needed: 206, Available: 186},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			encoder, err := NewEncoder(tc.mode, tc.opts...)
+			if err == nil {
+				_, err = encoder.Encode(tc.inputData)
+			}
+
+			if !errors.Is(err, tc.expErr.Err) {
+				t.Fatalf("expected %v, got %v", tc.expErr.Err, err)
+			}
+
+			var encodeErr *EncodeError
+			if !errors.As(err, &encodeErr) {
+				t.Fatalf("expected an *EncodeError, got %T", err)
+			}
+
+			// Compare the fields only, *EncodeError itself compares by its message.
+			type fields struct {
+				Field                     Field
+				Offset, Needed, Available int
+			}
+
+			expected := fields{tc.expErr.Field, tc.expErr.Offset, tc.expErr.Needed, tc.expErr.Available}
+			got := fields{encodeErr.Field, encodeErr.Offset, encodeErr.Needed, encodeErr.Available}
+
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Errorf("Error is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
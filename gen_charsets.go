@@ -0,0 +1,77 @@
+//go:build ignore
+
+// This program generates charsets_table.go from golang.org/x/text. It is not
+// a dependency of the package, run it from a module that requires x/text:
+//
+//	go run gen_charsets.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+var charsets = []struct {
+	eci     int
+	name    string
+	charmap *charmap.Charmap
+}{
+	{3, "ISO-8859-1", charmap.ISO8859_1},
+	{4, "ISO-8859-2", charmap.ISO8859_2},
+	{5, "ISO-8859-3", charmap.ISO8859_3},
+	{6, "ISO-8859-4", charmap.ISO8859_4},
+	{7, "ISO-8859-5", charmap.ISO8859_5},
+	{8, "ISO-8859-6", charmap.ISO8859_6},
+	{9, "ISO-8859-7", charmap.ISO8859_7},
+	{10, "ISO-8859-8", charmap.ISO8859_8},
+	{11, "ISO-8859-9", charmap.ISO8859_9},
+	{12, "ISO-8859-10", charmap.ISO8859_10},
+	{15, "ISO-8859-13", charmap.ISO8859_13},
+	{16, "ISO-8859-14", charmap.ISO8859_14},
+	{17, "ISO-8859-15", charmap.ISO8859_15},
+	{18, "ISO-8859-16", charmap.ISO8859_16},
+	{21, "Windows-1250", charmap.Windows1250},
+	{22, "Windows-1251", charmap.Windows1251},
+	{23, "Windows-1252", charmap.Windows1252},
+	{24, "Windows-1256", charmap.Windows1256},
+}
+
+func main() {
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "// Code generated by gen_charsets.go; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package maxicode")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "var charsets = []charset{")
+
+	for _, cs := range charsets {
+		fmt.Fprintf(&buf, "{eci: %d, name: %q, high: [128]rune{", cs.eci, cs.name)
+
+		for b := 0x80; b <= 0xff; b++ {
+			if (b-0x80)%8 == 0 {
+				fmt.Fprintln(&buf)
+			}
+
+			fmt.Fprintf(&buf, "%#04x, ", cs.charmap.DecodeByte(byte(b)))
+		}
+
+		fmt.Fprintln(&buf, "\n}},")
+	}
+
+	fmt.Fprintln(&buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile("charsets_table.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
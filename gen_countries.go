@@ -0,0 +1,75 @@
+//go:build ignore
+
+// This program generates countries_table.go from the ISO 3166-1 data of the
+// iso-codes project:
+//
+//	go run gen_countries.go [/usr/share/iso-codes/json/iso_3166-1.json]
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+)
+
+func main() {
+	path := "/usr/share/iso-codes/json/iso_3166-1.json"
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var iso struct {
+		Countries []struct {
+			Alpha2  string `json:"alpha_2"`
+			Alpha3  string `json:"alpha_3"`
+			Numeric string `json:"numeric"`
+			Name    string `json:"name"`
+		} `json:"3166-1"`
+	}
+
+	if err := json.Unmarshal(data, &iso); err != nil {
+		log.Fatal(err)
+	}
+
+	sort.Slice(iso.Countries, func(i, j int) bool {
+		return iso.Countries[i].Numeric < iso.Countries[j].Numeric
+	})
+
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "// Code generated by gen_countries.go; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package maxicode")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "var countries = []Country{")
+
+	for _, c := range iso.Countries {
+		numeric, err := strconv.Atoi(c.Numeric)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Fprintf(&buf, "{Numeric: %d, Alpha2: %q, Alpha3: %q, Name: %q},\n", numeric, c.Alpha2, c.Alpha3, c.Name)
+	}
+
+	fmt.Fprintln(&buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile("countries_table.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
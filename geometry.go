@@ -0,0 +1,49 @@
+package maxicode
+
+// Symbol geometry in millimetres, shared by every renderer and the scanner.
+const (
+	symbolWidth  = 28.0
+	symbolHeight = 26.8
+
+	bullseyeX         = 13.64
+	bullseyeY         = 13.43
+	bullseyeLineWidth = 0.67
+
+	// modulePitch is the distance between the centres of neighbouring
+	// modules in a row.
+	modulePitch = 0.88
+)
+
+// bullseyeRadii are the radii of the three rings, outermost first.
+var bullseyeRadii = [3]float64{3.54, 2.20, 0.85}
+
+// point is a position in millimetres from the top left corner of the symbol.
+type point struct {
+	x, y float64
+}
+
+// hexagon returns the corners of the module at row and column, clockwise
+// from the top.
+func hexagon(row, column int) [6]point {
+	const (
+		w = 0.76
+		h = 0.88
+	)
+
+	rowOffset := modulePitch
+	if row&1 == 1 {
+		rowOffset = 1.32
+	}
+
+	x := float64(column)*modulePitch + rowOffset
+	y := float64(row)*0.76 + 0.76
+
+	return [6]point{
+		{x + w*0.5, y},
+		{x + w, y + h*0.25},
+		{x + w, y + h*0.75},
+		{x + w*0.5, y + h},
+		{x, y + h*0.75},
+		{x, y + h*0.25},
+	}
+}
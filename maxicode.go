@@ -93,106 +93,204 @@ var (
 	rsEcc10 = readsolomon.NewEncoder(0x43, 10, 1)
 	rsEcc20 = readsolomon.NewEncoder(0x43, 20, 1)
 	rsEcc28 = readsolomon.NewEncoder(0x43, 28, 1)
+
+	rsDec10 = readsolomon.NewDecoder(0x43, 10, 1)
+	rsDec20 = readsolomon.NewDecoder(0x43, 20, 1)
+	rsDec28 = readsolomon.NewDecoder(0x43, 28, 1)
 )
 
+// structuredAppend places a symbol within a sequence of up to 8 symbols
+// carrying one message. The zero value means a standalone symbol.
+type structuredAppend struct {
+	position int
+	total    int
+}
+
 func Encode(mode, eci int, inputData string) (*SymbolGrid, error) {
-	scmHeader := "[)>" + RS + "01" + GS
+	encoder, err := NewEncoder(Mode(mode), WithECI(eci))
+	if err != nil {
+		return nil, err
+	}
+
+	grids, err := encoder.Encode(inputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return grids[0], nil
+}
+
+// EncodeAuto encodes inputData in the mode that suits it and returns the mode
+// it picked, see Encoder.SelectMode.
+func EncodeAuto(eci int, inputData string) (*SymbolGrid, Mode, error) {
+	encoder, err := NewEncoder(ModeAuto, WithECI(eci))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mode, err := encoder.SelectMode(inputData)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	grids, err := encoder.Encode(inputData)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return grids[0], mode, nil
+}
+
+// EncodeStructured encodes a mode 2 or 3 symbol from a primary message and
+// free-form secondary data, without the structured carrier message envelope
+// that Encode expects. Only the ISO/IEC 16023 rules apply, mode 2 is used for
+// numeric postcodes and mode 3 otherwise.
+func EncodeStructured(primary Primary, secondary string) (*SymbolGrid, error) {
+	encoder, err := NewEncoder(ModeAuto, WithPrimary(primary), WithValidation(ValidationISO))
+	if err != nil {
+		return nil, err
+	}
+
+	grids, err := encoder.Encode(secondary)
+	if err != nil {
+		return nil, err
+	}
+
+	return grids[0], nil
+}
+
+// EncodeBytes encodes an opaque binary payload in mode 4, 5 or 6 without
+// treating it as text.
+func EncodeBytes(mode int, data []byte) (*SymbolGrid, error) {
+	encoder, err := NewEncoder(Mode(mode))
+	if err != nil {
+		return nil, err
+	}
 
-	if mode < 2 || mode > 6 {
-		return nil, errors.New("only modes 2 to 6 supported")
+	grids, err := encoder.EncodeBytes(data)
+	if err != nil {
+		return nil, err
 	}
 
+	return grids[0], nil
+}
+
+// EncodeStructuredAppend encodes a message that may be too long for one symbol
+// into a sequence of up to 8 symbols. In modes 2 and 3 every symbol carries the
+// same primary message. A message that fits in one symbol is encoded as usual.
+func EncodeStructuredAppend(mode, eci int, inputData string) ([]*SymbolGrid, error) {
+	encoder, err := NewEncoder(Mode(mode), WithECI(eci), WithStructuredAppend(8))
+	if err != nil {
+		return nil, err
+	}
+
+	return encoder.Encode(inputData)
+}
+
+// processPrimary fills in the primary message and returns the data left for the secondary message.
+func processPrimary(mode Mode, inputData string, validation Validation, warn func(Warning)) (maxiCodewords, string, error) {
+	scmHeader := "[)>" + RS + "01" + GS
+
 	codewords := make(maxiCodewords, 144)
 	secondaryData := ""
 
 	if mode == 2 || mode == 3 {
 		if !strings.HasPrefix(inputData, scmHeader) {
-			return nil, errors.New("invalid mode 2 / mode 3 structured carrier message header")
+			return nil, "", newEncodeError(ErrInvalidHeader, FieldHeader, 0, "invalid mode 2 / mode 3 structured carrier message header")
 		}
 
-		// Header + postcode + country code + service class + tracking code + SCAC + EOT (44 characters mode 2, 41 for mode 3).
-		minLen := 41
+		// Header + postcode + country code + service class + tracking code + SCAC + EOT (36 characters for
+		// a 1 digit postcode in mode 2, 35 for an empty one in mode 3). The ISO profiles only need the
+		// primary fields.
+		minLen := 35
 		if mode == 2 {
-			minLen = 44
+			minLen = 36
 		}
 
-		if len(inputData) < minLen {
-			return nil, errors.New("input data is shorter than mandatory UPS requirements")
-		}
-
-		if !strings.HasSuffix(inputData, EOT) {
-			return nil, errors.New("input data should end with EOT marker")
+		if validation.requiresUPSFields() && len(inputData) < minLen {
+			return nil, "", newEncodeError(ErrInvalidHeader, FieldHeader, len(inputData), "input data is shorter than mandatory UPS requirements")
 		}
 
 		// Extract the postcode, country code and service class and save in the primary data buffer.
-		// Note: Group separators GS are removed.
+		// Note: Group separators GS are removed. The other fields are left as they are, the UPS
+		// validation profile checks them.
 
 		const postcodeStart = 9
 
-		groups := strings.Split(inputData[postcodeStart:], GS)
-		if len(groups) < 3 {
-			return nil, errors.New("input data should contain postcode, country code and service class separated by Gs")
+		primary, restStart, err := splitPrimary(inputData)
+		if err != nil {
+			return nil, "", err
 		}
 
-		postcode := groups[0]
-		countryCode := groups[1]
-		serviceClass := groups[2]
+		countryStart := postcodeStart + len(primary.Postcode) + 1
 
-		if l := len(postcode); (mode == 2 && l != 9) || (mode == 3 && l != 6) {
-			return nil, errors.New("invalid postcode length")
+		if err := codewords.processPrimaryMessage(mode, primary, postcodeStart, countryStart, validation, warn); err != nil {
+			return nil, "", err
 		}
 
-		if len(countryCode) != 3 {
-			return nil, errors.New("invalid country code length")
-		}
+		// Copy the header and the 2 digit year, then the rest of the data into the secondary data buffer.
+		secondaryData = inputData[:postcodeStart] + inputData[restStart:]
 
-		if len(serviceClass) != 3 {
-			return nil, errors.New("invalid service class length")
-		}
+	} else {
+		// Not using a primary, just copy all the data into the secondary data buffer.
+		codewords[0] = int(mode)
+		secondaryData = inputData
+	}
 
-		cc, err := strconv.Atoi(countryCode)
-		if err != nil {
-			return nil, errors.New("country code must be numeric")
-		}
+	return codewords, secondaryData, nil
+}
 
-		sc, err := strconv.Atoi(serviceClass)
-		if err != nil {
-			return nil, errors.New("service class must be numeric")
-		}
+// processPrimaryMessage checks the primary message and stores it in the
+// first 10 codewords. postcodeStart and countryStart locate the fields in the
+// input data for errors, -1 when they do not come from it.
+func (codewords maxiCodewords) processPrimaryMessage(mode Mode, primary Primary, postcodeStart, countryStart int, validation Validation, warn func(Warning)) error {
+	postcode, cc, sc := primary.Postcode, primary.CountryCode, primary.ServiceClass
 
-		if mode == 2 {
-			if cc != 840 {
-				return nil, errors.New("mode 2 requires US country code 840")
-			}
+	if mode == 2 && validation.dropsPostcodeSeparators() {
+		postcode = mode2Postcode(postcode, postcodeStart, warn)
+	}
 
-			pc, err := strconv.Atoi(postcode)
-			if err != nil {
-				return nil, errors.New("postcode must be numeric in mode 2")
-			}
+	if l := len(postcode); mode == 2 && (l < 1 || l > 9) {
+		return newEncodeError(ErrInvalidPostcode, FieldPostcode, postcodeStart, "invalid postcode length")
+	}
 
-			codewords.processPrimaryMode2(pc, cc, sc)
-		} else {
-			codewords.processPrimaryMode3(postcode, cc, sc)
-		}
+	if validation.knownCountriesOnly() && !knownCountry(cc) {
+		return newEncodeError(ErrInvalidCountry, FieldCountry, countryStart, fmt.Sprintf("country code %03d is not an ISO 3166 country", cc))
+	}
 
-		// Copy the header and the 2 digit year into the secondary data buffer.
-		secondaryData = inputData[:9]
+	if sc < 0 || sc > 999 {
+		return newEncodeError(ErrInvalidServiceClass, FieldServiceClass, -1, fmt.Sprintf("service class %d is out of range 0 to 999", sc))
+	}
 
-		// Copy the rest of the data into the secondary data buffer.
-		if len(groups) > 3 {
-			secondaryData += strings.Join(groups[3:], GS)
+	if mode == 2 {
+		if cc != 840 && validation.usOnlyMode2() {
+			return newEncodeError(ErrInvalidCountry, FieldCountry, countryStart, "mode 2 requires US country code 840")
 		}
 
-	} else {
-		// Not using a primary, just copy all the data into the secondary data buffer.
-		codewords[0] = mode
-		secondaryData = inputData
+		pc, err := strconv.Atoi(postcode)
+		if err != nil || !isDigits(postcode) {
+			return newEncodeError(ErrInvalidPostcode, FieldPostcode, postcodeStart, "postcode must be numeric in mode 2")
+		}
+
+		codewords.processPrimaryMode2(pc, len(postcode), cc, sc)
+
+		return nil
 	}
 
-	if err := codewords.processSecondary(mode, eci, secondaryData); err != nil {
-		return nil, err
+	country, _ := LookupCountry(fmt.Sprintf("%03d", cc))
+
+	pc, err := mode3Postcode(postcode, country, postcodeStart, validation.replacesPostcodeChars(warn), warn)
+	if err != nil {
+		return err
 	}
 
+	codewords.processPrimaryMode3(pc, cc, sc)
+
+	return nil
+}
+
+// symbolGrid adds error correction and lays the codewords out in a symbol.
+func (codewords maxiCodewords) symbolGrid(mode Mode) *SymbolGrid {
 	// All the data is sorted - now do error correction.
 	codewords.primaryDataCheck()
 
@@ -240,14 +338,14 @@ func Encode(mode, eci int, inputData string) (*SymbolGrid, error) {
 	grid.SetModule(22, 17, true) // Bottom right marker
 	grid.SetModule(23, 17, true)
 
-	return &grid, nil
+	return &grid
 }
 
 type maxiCodewords []int
 
-func (codewords maxiCodewords) processPrimaryMode2(postcode, countryCode, serviceClass int) {
-	const postcodeLen = 9
-
+// processPrimaryMode2 stores the number of postcode digits next to their value,
+// so that leading zeros and postcodes shorter than 9 digits survive decoding.
+func (codewords maxiCodewords) processPrimaryMode2(postcode, postcodeLen, countryCode, serviceClass int) {
 	codewords[0] = ((postcode & 0x03) << 4) | 2
 	codewords[1] = (postcode & 0xfc) >> 2
 	codewords[2] = (postcode & 0x3f00) >> 8
@@ -316,20 +414,110 @@ func determineSet(set []int, i int, allowedSets ...int) int {
 	return allowedSets[0]
 }
 
-func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData string) error {
+func (codewords maxiCodewords) processSecondary(mode Mode, strategy CodeSetStrategy, segments []Segment, sa structuredAppend) error {
+	character, dataLen, err := secondaryCodewords(mode, strategy, segments, sa)
+	if err != nil {
+		return err
+	}
+
+	if capacity := secondaryCapacity(mode); dataLen > capacity {
+		return tooLongError(dataLen, capacity, fmt.Sprintf("input data is too long, needs %d codewords but %v has room for %d", dataLen, mode, capacity))
+	}
+
+	switch mode {
+	case 2, 3:
+		for i := 0; i < 84; i++ {
+			codewords[i+20] = character[i]
+		}
+	case 4, 6:
+		// Primary
+		for i := 0; i < 9; i++ {
+			codewords[i+1] = character[i]
+		}
+
+		// Secondary
+		for i := 0; i < 84; i++ {
+			codewords[i+20] = character[i+9]
+		}
+	case 5:
+		// Primary
+		for i := 0; i < 9; i++ {
+			codewords[i+1] = character[i]
+		}
+
+		// Secondary
+		for i := 0; i < 68; i++ {
+			codewords[i+20] = character[i+9]
+		}
+	}
+
+	return nil
+}
+
+// secondaryCodewords returns the padded codewords of the secondary data and
+// the number of them that are needed, using the code set strategy.
+func secondaryCodewords(mode Mode, strategy CodeSetStrategy, segments []Segment, sa structuredAppend) ([]int, int, error) {
+	if strategy == CodeSetOptimal {
+		return secondaryOptimal(mode, segments, sa)
+	}
+
+	return secondaryGreedy(mode, segments, sa)
+}
+
+// secondaryCapacity is the number of codewords available to the secondary
+// data, including the part of it that goes into the primary message.
+func secondaryCapacity(mode Mode) int {
+	switch mode {
+	case 2, 3:
+		return 84
+	case 5:
+		return 77
+	default:
+		return 93
+	}
+}
+
+// secondaryGreedy picks code sets by looking a few characters ahead. It returns
+// the padded codewords and the number of them that are needed.
+func secondaryGreedy(mode Mode, segments []Segment, sa structuredAppend) ([]int, int, error) {
 	// Format text according to Appendix A
 
-	if len(secondaryData) > 138 {
-		return errors.New("input data is too long")
+	// Work on at least a full symbol, with room for a shift or latch in front
+	// of every character of longer messages so their exact length is known.
+	size := 144
+	for _, segment := range segments {
+		size += 2*len(segment.Data) + 5
 	}
 
-	set := make([]int, 144)
-	character := make([]int, 144)
+	set := make([]int, size)
+	character := make([]int, size)
+
+	dataLen := 0
+	eci := 0
+
+	for _, segment := range segments {
+		// ECI designators go in between the characters as set 7, the shift
+		// and latch logic passes over them.
+		if segment.ECI != eci {
+			for _, c := range eciCodewords(segment.ECI) {
+				set[dataLen] = 7
+				character[dataLen] = c
+				dataLen++
+			}
+
+			eci = segment.ECI
+		}
+
+		for i := 0; i < len(segment.Data); i++ {
+			// Look up characters in table from Appendix A - this gives value and code set for most characters.
+			set[dataLen] = maxiCodeSet[segment.Data[i]]
+			character[dataLen] = maxiSymbolChar[segment.Data[i]]
+			dataLen++
+		}
+	}
 
-	for i := 0; i < len(secondaryData); i++ {
-		// Look up characters in table from Appendix A - this gives value and code set for most characters.
-		set[i] = maxiCodeSet[secondaryData[i]]
-		character[i] = maxiSymbolChar[secondaryData[i]]
+	if dataLen == 0 {
+		return nil, 0, errors.New("secondary message is empty")
 	}
 
 	// If a character can be represented in more than one code set, pick which version to use.
@@ -341,8 +529,6 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 		set[0] = 1
 	}
 
-	dataLen := len(secondaryData)
-
 	for i := 1; i < dataLen; i++ {
 		// special characters
 		if set[i] == 0 {
@@ -414,7 +600,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 	}
 
 	// Add the padding
-	for i := dataLen; i < 144; i++ {
+	for i := dataLen; i < size; i++ {
 		if set[dataLen-1] == 2 {
 			set[i] = 2
 		} else {
@@ -434,7 +620,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 	}
 
 	count := 0
-	for i := start; i < 144; i++ {
+	for i := start; i < size; i++ {
 		// Character is a number
 		if set[i] == 1 && character[i] >= 48 && character[i] <= 57 {
 			count++
@@ -457,14 +643,14 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 	idx := 0
 
 	for {
-		if (set[idx] != currSet) && (set[idx] != 6) {
+		if (set[idx] != currSet) && (set[idx] != 6) && (set[idx] != 7) {
 			switch set[idx] {
 			case 1:
 				if currSet == 2 {
 					// Set B
-					if idx+1 < 144 && set[idx+1] == 1 {
-						if idx+2 < 144 && set[idx+2] == 1 {
-							if idx+3 < 144 && set[idx+3] == 1 {
+					if idx+1 < size && set[idx+1] == 1 {
+						if idx+2 < size && set[idx+2] == 1 {
+							if idx+3 < size && set[idx+3] == 1 {
 								// Latch A
 								insertPosition(set, character, idx, &dataLen)
 								character[idx] = 63 // Set B Latch A
@@ -497,7 +683,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 			case 2:
 				// Set B
 				// If not Set A or next Set B
-				if currSet != 1 || (idx+1 < 144 && set[idx+1] == 2) {
+				if currSet != 1 || (idx+1 < size && set[idx+1] == 2) {
 					// Latch B
 					insertPosition(set, character, idx, &dataLen)
 					character[idx] = 63 // Sets A,C,D,E Latch B
@@ -511,7 +697,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 			case 3, 4, 5:
 				// Set C, D, E
 				// If first and next 3 same set, or not first and previous and next 2 same set
-				if (idx == 0 && idx+3 < 144 && set[idx+1] == set[idx] && set[idx+2] == set[idx] && set[idx+3] == set[idx]) || (idx > 0 && set[idx-1] == set[idx] && idx+2 < 144 && set[idx+1] == set[idx] && set[idx+2] == set[idx]) {
+				if (idx == 0 && idx+3 < size && set[idx+1] == set[idx] && set[idx+2] == set[idx] && set[idx+3] == set[idx]) || (idx > 0 && set[idx-1] == set[idx] && idx+2 < size && set[idx+1] == set[idx] && set[idx+2] == set[idx]) {
 					if idx == 0 {
 						// Lock in C/D/E
 						insertPosition(set, character, idx, &dataLen)
@@ -522,7 +708,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 						idx += 3 // Next 3 same set so skip over
 					} else {
 						// Add single Shift to previous Shift
-						insertPosition(set, character, idx, &dataLen)
+						insertPosition(set, character, idx-1, &dataLen)
 						character[idx-1] = 60 + set[idx] - 3
 						idx += 2 // Next 2 same set so skip over
 					}
@@ -537,7 +723,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 		}
 		idx++
 
-		if idx >= 144 {
+		if idx >= size {
 			break
 		}
 	}
@@ -553,7 +739,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 
 			value, err := strconv.Atoi(compressed)
 			if err != nil {
-				return fmt.Errorf("failed to convert compressed number: %w", err)
+				return nil, 0, fmt.Errorf("failed to convert compressed number: %w", err)
 			}
 
 			character[idx] = 31 // NS
@@ -564,7 +750,7 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 			character[idx+5] = value & 0x3f
 
 			idx += 6
-			for j := idx; j < 140; j++ {
+			for j := idx; j < size-4; j++ {
 				set[j] = set[j+3]
 				character[j] = character[j+3]
 			}
@@ -574,83 +760,21 @@ func (codewords maxiCodewords) processSecondary(mode, eci int, secondaryData str
 			idx++
 		}
 
-		if idx >= 144 {
+		if idx >= size {
 			break
 		}
 	}
 
-	// Insert ECI at the beginning of message if needed.
-	// Encode ECI assignment numbers according to table 3.
-	if eci != 0 {
+	// Structured append goes in front of everything else as PAD followed by
+	// the symbol position and the total number of symbols.
+	if sa.total > 0 {
 		insertPosition(set, character, 0, &dataLen)
-		character[0] = 27 // ECI
-
-		switch {
-		case eci <= 31:
-			insertPosition(set, character, 1, &dataLen)
-			character[1] = eci
-
-		case eci >= 32 && eci <= 1023:
-			insertPosition(set, character, 1, &dataLen)
-			insertPosition(set, character, 1, &dataLen)
-			insertPosition(set, character, 1, &dataLen)
-			character[1] = 0x30 + ((eci >> 12) & 0x03)
-			character[2] = (eci >> 6) & 0x3f
-			character[3] = eci & 0x3f
-			dataLen += 4
-
-		case eci >= 32768:
-			insertPosition(set, character, 1, &dataLen)
-			insertPosition(set, character, 1, &dataLen)
-			insertPosition(set, character, 1, &dataLen)
-			insertPosition(set, character, 1, &dataLen)
-			character[1] = 0x38 + ((eci >> 18) & 0x02)
-			character[2] = (eci >> 12) & 0x3f
-			character[3] = (eci >> 6) & 0x3f
-			character[4] = eci & 0x3f
-		}
-	}
-
-	switch mode {
-	case 2, 3:
-		if dataLen > 84 {
-			return errors.New("input data is too long")
-		}
-
-		for i := 0; i < 84; i++ {
-			codewords[i+20] = character[i]
-		}
-	case 4, 6:
-		if dataLen > 93 {
-			return errors.New("input data is too long")
-		}
-
-		// Primary
-		for i := 0; i < 9; i++ {
-			codewords[i+1] = character[i]
-		}
-
-		// Secondary
-		for i := 0; i < 84; i++ {
-			codewords[i+20] = character[i+9]
-		}
-	case 5:
-		if dataLen > 77 {
-			return errors.New("input data is too long")
-		}
-
-		// Primary
-		for i := 0; i < 9; i++ {
-			codewords[i+1] = character[i]
-		}
-
-		// Secondary
-		for i := 0; i < 68; i++ {
-			codewords[i+20] = character[i+9]
-		}
+		insertPosition(set, character, 0, &dataLen)
+		character[0] = 33 // PAD
+		character[1] = ((sa.position - 1) << 3) | (sa.total - 1)
 	}
 
-	return nil
+	return character, dataLen, nil
 }
 
 func (codewords maxiCodewords) primaryDataCheck() {
@@ -713,8 +837,23 @@ func (codewords maxiCodewords) secondaryDataCheckOdd(eccLen int) {
 	}
 }
 
+// eciCodewords returns the ECI designator followed by the assignment number
+// encoded according to table 3.
+func eciCodewords(eci int) []int {
+	switch {
+	case eci <= 31:
+		return []int{27, eci}
+	case eci <= 1023:
+		return []int{27, 0x20 | ((eci >> 6) & 0x0f), eci & 0x3f}
+	case eci <= 32767:
+		return []int{27, 0x30 | ((eci >> 12) & 0x07), (eci >> 6) & 0x3f, eci & 0x3f}
+	default:
+		return []int{27, 0x38 | ((eci >> 18) & 0x03), (eci >> 12) & 0x3f, (eci >> 6) & 0x3f, eci & 0x3f}
+	}
+}
+
 func insertPosition(set, character []int, position int, dataLen *int) {
-	for i := 143; i > position; i-- {
+	for i := len(set) - 1; i > position; i-- {
 		set[i] = set[i-1]
 		character[i] = character[i-1]
 	}
@@ -732,3 +871,14 @@ func getRsEncoder(eccLen int) *readsolomon.Encoder {
 		return rsEcc28
 	}
 }
+
+func getRsDecoder(eccLen int) *readsolomon.Decoder {
+	switch eccLen {
+	case 10:
+		return rsDec10
+	case 20:
+		return rsDec20
+	default:
+		return rsDec28
+	}
+}
@@ -3,6 +3,7 @@ package maxicode
 import (
 	"bytes"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -60,3 +61,99 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeStructuredAppend(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		mode       int
+		inputData  string
+		expSymbols int
+		expErr     bool
+	}{
+		{
+			desc:       "fits in one symbol",
+			mode:       4,
+			inputData:  "SHORT MESSAGE",
+			expSymbols: 1,
+		},
+		{
+			desc:       "mode 3 hazmat secondary data",
+			mode:       3,
+			inputData:  "[)>" + RS + "01" + GS + "09651147" + GS + "276" + GS + "066" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "N" + GS + "5 WALDSTRASSE" + GS + "COLOGNE" + GS + "" + RS + "07" + strings.Repeat("UN1845 DRY ICE 9 III 2.5 KG, ", 6) + RS + EOT,
+			expSymbols: 4,
+		},
+		{
+			desc:       "mode 5 customs data",
+			mode:       5,
+			inputData:  strings.Repeat("HS 6109.10 COTTON T-SHIRTS QTY 0000000012 EUR 0000000199 ", 5),
+			expSymbols: 4,
+		},
+		{
+			desc:      "more than 8 symbols",
+			mode:      4,
+			inputData: strings.Repeat("abcdefghijklmnopqrstuvwxyz", 40),
+			expErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			grids, err := EncodeStructuredAppend(tc.mode, 0, tc.inputData)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(grids) != tc.expSymbols {
+				t.Fatalf("expected %d symbols, got %d", tc.expSymbols, len(grids))
+			}
+
+			var results []Result
+
+			// Decode in reverse order, Reassemble has to sort them.
+			for i := len(grids) - 1; i >= 0; i-- {
+				result, err := Decode(grids[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(grids) > 1 && (result.Position != i+1 || result.Total != len(grids)) {
+					t.Errorf("expected symbol %d of %d, got %d of %d", i+1, len(grids), result.Position, result.Total)
+				}
+
+				results = append(results, *result)
+			}
+
+			message, err := Reassemble(results)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.inputData, message.Data); diff != "" {
+				t.Errorf("Reassembled message is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSecondaryGreedyLock(t *testing.T) {
+	// A run of set C characters after set A locks set C with a second shift
+	// right after the first one. Inserting the second shift after the first
+	// character of the run instead overwrote that character.
+	character, dataLen, err := secondaryGreedy(Mode4, []Segment{{Data: "A\xc4\xd6\xdc\xc5B"}}, structuredAppend{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []int{1, 60, 60, 4, 22, 33, 5, 58, 2}
+	if diff := cmp.Diff(expected, character[:dataLen]); diff != "" {
+		t.Errorf("Codewords are not equal to expected, diff (-want, +got):\n%s", diff)
+	}
+}
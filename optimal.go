@@ -0,0 +1,254 @@
+package maxicode
+
+import "errors"
+
+// setValue is the symbol value of a character within one code set.
+type setValue struct {
+	set   int
+	value int
+}
+
+// maxiCharValues lists every code set a character can be written in, it is
+// the reverse of maxiSetChar.
+var maxiCharValues = buildCharValues()
+
+func buildCharValues() [256][]setValue {
+	var table [256][]setValue
+
+	for set := range maxiSetChar {
+		for value, c := range maxiSetChar[set] {
+			if c >= 0 {
+				table[c] = append(table[c], setValue{set, value})
+			}
+		}
+	}
+
+	return table
+}
+
+// secondaryItem is either a character or a complete ECI designator.
+type secondaryItem struct {
+	char       byte
+	designator []int
+}
+
+// step is a node in the search for the shortest codeword stream: the cheapest
+// way found so far to reach an item while latched into a code set.
+type step struct {
+	cost    int
+	prev    int
+	emitted []int
+}
+
+// secondaryOptimal finds the shortest codeword stream for the secondary data
+// by dynamic programming over the code set the reader is latched into. It
+// returns the padded codewords and the number of them that are needed.
+func secondaryOptimal(mode Mode, segments []Segment, sa structuredAppend) ([]int, int, error) {
+	var items []secondaryItem
+
+	eci := 0
+	for _, segment := range segments {
+		if segment.ECI != eci {
+			items = append(items, secondaryItem{designator: eciCodewords(segment.ECI)})
+			eci = segment.ECI
+		}
+
+		for i := 0; i < len(segment.Data); i++ {
+			items = append(items, secondaryItem{char: segment.Data[i]})
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, 0, errors.New("secondary message is empty")
+	}
+
+	capacity := secondaryCapacity(mode)
+
+	// Structured append goes in front of everything else as PAD followed by
+	// the symbol position and the total number of symbols.
+	var prefix []int
+	if sa.total > 0 {
+		prefix = []int{33, ((sa.position - 1) << 3) | (sa.total - 1)}
+	}
+
+	// Steps are indexed by item, latched set and the cost so far capped at 9,
+	// because number compression may not start within the primary message of
+	// modes 4 to 6.
+	const buckets = 10
+
+	index := func(item, set, cost int) int {
+		return ((item*5)+set)*buckets + min(cost, buckets-1)
+	}
+
+	steps := make([]*step, (len(items)+1)*5*buckets)
+	steps[index(0, 0, len(prefix))] = &step{cost: len(prefix), prev: -1}
+
+	relax := func(from, item, set int, emitted ...int) {
+		cost := steps[from].cost + len(emitted)
+		to := index(item, set, cost)
+
+		if steps[to] == nil || cost < steps[to].cost {
+			steps[to] = &step{cost: cost, prev: from, emitted: emitted}
+		}
+	}
+
+	for i, item := range items {
+		for set := 0; set < 5; set++ {
+			for bucket := 0; bucket < buckets; bucket++ {
+				from := ((i*5)+set)*buckets + bucket
+				current := steps[from]
+				if current == nil {
+					continue
+				}
+
+				if item.designator != nil {
+					relax(from, i+1, set, item.designator...)
+					continue
+				}
+
+				if value, ok := numericShift(items[i:]); ok && (mode == 2 || mode == 3 || current.cost >= 9) {
+					relax(from, i+9, set, 31,
+						(value>>24)&0x3f, (value>>18)&0x3f, (value>>12)&0x3f, (value>>6)&0x3f, value&0x3f)
+				}
+
+				for _, sv := range maxiCharValues[item.char] {
+					if sv.set == set {
+						relax(from, i+1, set, sv.value)
+						continue
+					}
+
+					relax(from, i+1, sv.set, append(latchCodewords(set, sv.set), sv.value)...)
+
+					if shift, ok := shiftCodeword(set, sv.set); ok {
+						relax(from, i+1, set, shift, sv.value)
+					}
+
+					// Set B can shift two or three characters into set A at once.
+					if set == 1 && sv.set == 0 {
+						emitted := []int{sv.value}
+
+						for n := 1; n < 3 && i+n < len(items); n++ {
+							value, ok := valueInSet(items[i+n], 0)
+							if !ok {
+								break
+							}
+
+							emitted = append(emitted, value)
+							relax(from, i+n+1, set, append([]int{56 + n - 1}, emitted...)...)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Pick the cheapest way to finish, sets C and D have no PAD and must
+	// latch back to set A first unless the symbol is full.
+	best, bestLen, bestSet := -1, 0, 0
+	for set := 0; set < 5; set++ {
+		for bucket := 0; bucket < buckets; bucket++ {
+			at := index(len(items), set, bucket)
+			if steps[at] == nil {
+				continue
+			}
+
+			n := steps[at].cost
+			if (set == 2 || set == 3) && n < capacity {
+				n++
+			}
+
+			if best == -1 || n < bestLen {
+				best, bestLen, bestSet = at, n, set
+			}
+		}
+	}
+
+	var stream [][]int
+	for at := best; at != -1; at = steps[at].prev {
+		stream = append(stream, steps[at].emitted)
+	}
+
+	character := make([]int, 0, 144)
+	character = append(character, prefix...)
+
+	for i := len(stream) - 1; i >= 0; i-- {
+		character = append(character, stream[i]...)
+	}
+
+	pad := 33
+	switch bestSet {
+	case 2, 3:
+		if len(character) < capacity {
+			character = append(character, 58) // Latch A
+		}
+	case 4:
+		pad = 28
+	}
+
+	for len(character) < 144 {
+		character = append(character, pad)
+	}
+
+	return character, bestLen, nil
+}
+
+// numericShift reports whether the next nine items are digits that fit a
+// single numeric shift, and their value.
+func numericShift(items []secondaryItem) (int, bool) {
+	if len(items) < 9 {
+		return 0, false
+	}
+
+	value := 0
+	for _, item := range items[:9] {
+		if item.designator != nil || item.char < '0' || item.char > '9' {
+			return 0, false
+		}
+
+		value = (value * 10) + int(item.char-'0')
+	}
+
+	return value, true
+}
+
+func valueInSet(item secondaryItem, set int) (int, bool) {
+	if item.designator != nil {
+		return 0, false
+	}
+
+	for _, sv := range maxiCharValues[item.char] {
+		if sv.set == set {
+			return sv.value, true
+		}
+	}
+
+	return 0, false
+}
+
+// latchCodewords returns the codewords that move the reader from one code set
+// to another for good. Sets C, D and E are locked in by shifting twice.
+func latchCodewords(from, to int) []int {
+	switch {
+	case to == 0 && from == 1:
+		return []int{63}
+	case to == 0:
+		return []int{58}
+	case to == 1:
+		return []int{63}
+	default:
+		return []int{60 + to - 2, 60 + to - 2}
+	}
+}
+
+// shiftCodeword returns the codeword that reads only the next character in
+// another code set. Sets C, D and E can only shift into each other.
+func shiftCodeword(from, to int) (int, bool) {
+	switch {
+	case to >= 2:
+		return 60 + to - 2, true
+	case from <= 1:
+		return 59, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,125 @@
+package maxicode
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSecondaryOptimal checks that the optimal encoder never needs more
+// codewords than the greedy one, and that readers understand its output.
+func TestSecondaryOptimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	// Character pools that make the greedy encoder switch code sets often.
+	pools := []string{
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 ,./:",
+		"abcdefghijklmnopqrstuvwxyz{|}~",
+		"\xc0\xc1\xc2\xe0\xe1\xe2\xa1\xa2\xa3\x01\x02\x03\r\x1c\x1d\x1e",
+		"0123456789",
+	}
+
+	for i := 0; i < 3000; i++ {
+		mode := Mode(2 + r.Intn(5))
+		if mode == 2 || mode == 3 {
+			mode += 2
+		}
+
+		var segments []Segment
+		for n := 1 + r.Intn(2); n > 0; n-- {
+			b := make([]byte, 1+r.Intn(60))
+			pool := pools[r.Intn(len(pools))]
+
+			for j := range b {
+				if r.Intn(4) == 0 {
+					pool = pools[r.Intn(len(pools))]
+				}
+
+				b[j] = pool[r.Intn(len(pool))]
+			}
+
+			segments = append(segments, Segment{ECI: []int{0, 3, 4, 2000}[r.Intn(4)], Data: string(b)})
+		}
+
+		segments, err := normalizeSegments(segments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sa := structuredAppend{}
+		if r.Intn(4) == 0 {
+			sa = structuredAppend{1 + r.Intn(2), 2}
+		}
+
+		_, greedyLen, err := secondaryGreedy(mode, segments, sa)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, optimalLen, err := secondaryOptimal(mode, segments, sa)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if optimalLen > greedyLen {
+			t.Fatalf("%v %q: optimal needs %d codewords, greedy %d", mode, segments, optimalLen, greedyLen)
+		}
+
+		if optimalLen > secondaryCapacity(mode) {
+			continue
+		}
+
+		codewords := make(maxiCodewords, 144)
+		codewords[0] = int(mode)
+
+		if err := codewords.processSecondary(mode, CodeSetOptimal, segments, sa); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := Decode(codewords.symbolGrid(mode))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(segments, result.Segments); diff != "" {
+			t.Fatalf("%v: decoded segments are not equal to expected, diff (-want, +got):\n%s", mode, diff)
+		}
+	}
+}
+
+func TestEncoderCodeSetStrategy(t *testing.T) {
+	// Alternating between sets B and C makes the greedy encoder shift for
+	// every other character.
+	inputData := strings.Repeat("\xe4b", 24)
+
+	greedy, err := NewEncoder(Mode4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := greedy.Encode(inputData); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("expected greedy encoder to run out of space, got %v", err)
+	}
+
+	optimal, err := NewEncoder(Mode4, WithCodeSetStrategy(CodeSetOptimal))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grids, err := optimal.Encode(inputData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Decode(grids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Data != inputData {
+		t.Errorf("expected %q, got %q", inputData, result.Data)
+	}
+}
@@ -0,0 +1,148 @@
+package maxicode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDFPage is a page of a PDF document holding symbols, measured in
+// millimetres. A 4×6" label is 101.6 by 152.4, A4 is 210 by 297.
+type PDFPage struct {
+	Width   float64
+	Height  float64
+	Symbols []PDFSymbol
+}
+
+// PDFSymbol places a symbol on a page with its top left corner X and Y
+// millimetres from the top left corner of the page.
+type PDFSymbol struct {
+	Grid *SymbolGrid
+	X    float64
+	Y    float64
+}
+
+// ptPerMM converts millimetres to PDF points.
+const ptPerMM = 72 / 25.4
+
+// WritePDF writes a PDF document with the symbols drawn as vectors in the
+// same geometry as Draw.
+func WritePDF(w io.Writer, pages ...PDFPage) error {
+	if len(pages) == 0 {
+		return errors.New("PDF document needs at least one page")
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Objects are numbered from 1: the catalog, the page tree, then a page
+	// and its content stream for every page.
+	object := func(body string, stream []byte) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\n", len(offsets), body)
+		if stream != nil {
+			buf.WriteString("stream\n")
+			buf.Write(stream)
+			buf.WriteString("\nendstream\n")
+		}
+		buf.WriteString("endobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 3+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)), nil)
+
+	for i, page := range pages {
+		if page.Width <= 0 || page.Height <= 0 {
+			return fmt.Errorf("page %d has no size", i+1)
+		}
+
+		content, err := pdfContent(page)
+		if err != nil {
+			return fmt.Errorf("page %d: %w", i+1, err)
+		}
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << >> /Contents %d 0 R >>", decimal(page.Width*ptPerMM), decimal(page.Height*ptPerMM), 4+2*i), nil)
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>", len(content)), content)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// pdfContent returns the compressed content stream drawing the symbols of a
+// page. Every symbol is drawn in millimetres from its top left corner.
+func pdfContent(page PDFPage) ([]byte, error) {
+	var ops bytes.Buffer
+
+	for _, symbol := range page.Symbols {
+		if symbol.Grid == nil {
+			return nil, errors.New("symbol grid is nil")
+		}
+
+		fmt.Fprintf(&ops, "q %s 0 0 %s %s %s cm\n0 g 0 G %s w\n", decimal(ptPerMM), decimal(-ptPerMM), decimal(symbol.X*ptPerMM), decimal((page.Height-symbol.Y)*ptPerMM), decimal(bullseyeLineWidth))
+
+		// A circle is four Bézier curves, k places their control points.
+		const k = 0.5522847498
+		for _, r := range bullseyeRadii {
+			x, y, c := bullseyeX, bullseyeY, k*r
+			fmt.Fprintf(&ops, "%s %s m\n", decimal(x+r), decimal(y))
+			fmt.Fprintf(&ops, "%s %s %s %s %s %s c\n", decimal(x+r), decimal(y+c), decimal(x+c), decimal(y+r), decimal(x), decimal(y+r))
+			fmt.Fprintf(&ops, "%s %s %s %s %s %s c\n", decimal(x-c), decimal(y+r), decimal(x-r), decimal(y+c), decimal(x-r), decimal(y))
+			fmt.Fprintf(&ops, "%s %s %s %s %s %s c\n", decimal(x-r), decimal(y-c), decimal(x-c), decimal(y-r), decimal(x), decimal(y-r))
+			fmt.Fprintf(&ops, "%s %s %s %s %s %s c\n", decimal(x+c), decimal(y-r), decimal(x+r), decimal(y-c), decimal(x+r), decimal(y))
+			ops.WriteString("S\n")
+		}
+
+		for row := range 33 {
+			for column := range 30 {
+				if !symbol.Grid.GetModule(row, column) {
+					continue
+				}
+
+				for i, p := range hexagon(row, column) {
+					op := "l"
+					if i == 0 {
+						op = "m"
+					}
+
+					fmt.Fprintf(&ops, "%s %s %s ", decimal(p.x), decimal(p.y), op)
+				}
+
+				ops.WriteString("h\n")
+			}
+		}
+
+		ops.WriteString("f\nQ\n")
+	}
+
+	var compressed bytes.Buffer
+
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(ops.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressed.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package maxicode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWritePDF(t *testing.T) {
+	grid := testGrid(t)
+
+	modules := countModules(grid)
+
+	pages := []PDFPage{
+		{Width: 101.6, Height: 152.4, Symbols: []PDFSymbol{{Grid: grid, X: 10, Y: 10}}},
+		{Width: 210, Height: 297, Symbols: []PDFSymbol{{Grid: grid, X: 20, Y: 20}, {Grid: grid, X: 120, Y: 20}, {Grid: grid, X: 20, Y: 150}}},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, pages...); err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := buf.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	// Every cross-reference entry has to point at its object.
+	start, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(pdf)[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[start:], -1)
+	if len(entries) != 2+2*len(pages) {
+		t.Fatalf("expected %d objects, got %d", 2+2*len(pages), len(entries))
+	}
+
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if prefix := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(pdf[offset:], prefix) {
+			t.Errorf("object %d is not at offset %d", i+1, offset)
+		}
+	}
+
+	if !strings.Contains(pdf, "/MediaBox [0 0 288 432]") || !strings.Contains(pdf, "/MediaBox [0 0 595.2756 841.8898]") {
+		t.Error("unexpected page sizes")
+	}
+
+	if n := strings.Count(pdf, "/Resources << >>"); n != len(pages) {
+		t.Errorf("expected resources on %d pages, got %d", len(pages), n)
+	}
+
+	streams := regexp.MustCompile(`(?s)<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllStringSubmatchIndex(pdf, -1)
+	if len(streams) != len(pages) {
+		t.Fatalf("expected %d content streams, got %d", len(pages), len(streams))
+	}
+
+	for i, stream := range streams {
+		length, _ := strconv.Atoi(pdf[stream[2]:stream[3]])
+
+		zr, err := zlib.NewReader(strings.NewReader(pdf[stream[1] : stream[1]+length]))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		symbols := len(pages[i].Symbols)
+		if n := bytes.Count(content, []byte(" cm\n")); n != symbols {
+			t.Errorf("page %d: expected %d symbols, got %d", i+1, symbols, n)
+		}
+
+		if n := bytes.Count(content, []byte("S\n")); n != 3*symbols {
+			t.Errorf("page %d: expected %d bullseye rings, got %d", i+1, 3*symbols, n)
+		}
+
+		if n := bytes.Count(content, []byte("h\n")); n != modules*symbols {
+			t.Errorf("page %d: expected %d hexagons, got %d", i+1, modules*symbols, n)
+		}
+	}
+}
+
+func TestWritePDFErrors(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		pages []PDFPage
+	}{
+		{desc: "no pages"},
+		{desc: "no size", pages: []PDFPage{{}}},
+		{desc: "nil grid", pages: []PDFPage{{Width: 100, Height: 100, Symbols: []PDFSymbol{{}}}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := WritePDF(io.Discard, tc.pages...); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package maxicode
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// mode2Postcode drops the spaces and hyphens that numeric postcodes are often
+// written with, as in the "114 55" of Sweden or the "00-950" of Poland, so
+// that they fit mode 2. warn, when given, takes a Warning for every dropped
+// character. The postcode starts at offset in the message, or -1 when it is
+// not part of it.
+func mode2Postcode(postcode string, offset int, warn func(Warning)) string {
+	pc := make([]byte, 0, len(postcode))
+
+	for i := 0; i < len(postcode); i++ {
+		if c := postcode[i]; c != ' ' && c != '-' {
+			pc = append(pc, c)
+			continue
+		}
+
+		if warn != nil {
+			at := -1
+			if offset >= 0 {
+				at = offset + i
+			}
+
+			warn(Warning{Field: FieldPostcode, Offset: at, Message: fmt.Sprintf("%q at offset %d dropped from postcode %q", postcode[i], at, postcode)})
+		}
+	}
+
+	return string(pc)
+}
+
+// mode3Postcode turns a postcode into the 6 characters of a mode 3 primary
+// message. Letters are uppercased and the spaces and hyphens that separate
+// the parts of a postcode are dropped, as is a leading country prefix such
+// as the "LT-" of Lithuanian postcodes. Shorter postcodes are padded with
+// spaces.
+//
+// Longer postcodes and characters that code set A does not have are an
+// error, unless replace is set and the postcode is truncated and the
+// characters replaced with spaces. warn, when given, takes a Warning for
+// every replaced character and for truncation. Offsets count from the
+// start of the message, the postcode starts at offset, or -1 when it is not
+// part of the message.
+func mode3Postcode(postcode string, country Country, offset int, replace bool, warn func(Warning)) (string, error) {
+	const postcodeLen = 6
+
+	start := 0
+	for _, sep := range []string{"-", " "} {
+		if prefix := country.Alpha2 + sep; country.Alpha2 != "" && len(postcode) > len(prefix) && strings.EqualFold(postcode[:len(prefix)], prefix) {
+			start = len(prefix)
+		}
+	}
+
+	pc := make([]byte, 0, postcodeLen)
+
+	at := func(i int) int {
+		if offset < 0 {
+			return -1
+		}
+
+		return offset + i
+	}
+
+	for i := start; i < len(postcode); {
+		r, n := utf8.DecodeRuneInString(postcode[i:])
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+
+		switch {
+		case r == ' ' || r == '-':
+		case len(pc) == postcodeLen:
+			if !replace {
+				return "", newEncodeError(ErrInvalidPostcode, FieldPostcode, at(i), fmt.Sprintf("postcode %q is longer than %d characters", postcode, postcodeLen))
+			}
+
+			if warn != nil {
+				warn(Warning{Field: FieldPostcode, Offset: at(i), Message: fmt.Sprintf("postcode %q truncated to %q", postcode, pc)})
+			}
+
+			return string(pc), nil
+		case !validPostcodeChar(r):
+			msg := fmt.Sprintf("%q at offset %d is not a valid postcode character", r, at(i))
+			if !replace {
+				return "", newEncodeError(ErrInvalidPostcode, FieldPostcode, at(i), msg)
+			}
+
+			if warn != nil {
+				warn(Warning{Field: FieldPostcode, Offset: at(i), Message: msg + ", replaced with a space"})
+			}
+			pc = append(pc, ' ')
+		default:
+			pc = append(pc, byte(r))
+		}
+
+		i += n
+	}
+
+	for len(pc) < postcodeLen {
+		pc = append(pc, ' ')
+	}
+
+	return string(pc), nil
+}
+
+// validPostcodeChar reports whether code set A has the character. Control
+// characters stand in for the letters of code set A.
+func validPostcodeChar(r rune) bool {
+	return (r >= 'A' && r <= 'Z') || (r < 59 && r != 27 && r != 31 && r != 33)
+}
@@ -0,0 +1,150 @@
+package maxicode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMode2Postcode(t *testing.T) {
+	var warnings []Warning
+
+	postcode := mode2Postcode("01310-100 ", 10, func(w Warning) { warnings = append(warnings, w) })
+	if postcode != "01310100" {
+		t.Errorf("expected %q, got %q", "01310100", postcode)
+	}
+
+	expWarnings := []Warning{
+		{Field: FieldPostcode, Offset: 15, Message: `'-' at offset 15 dropped from postcode "01310-100 "`},
+		{Field: FieldPostcode, Offset: 19, Message: `' ' at offset 19 dropped from postcode "01310-100 "`},
+	}
+
+	if diff := cmp.Diff(expWarnings, warnings); diff != "" {
+		t.Errorf("Warnings are not equal to expected, diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestMode3Postcode(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		postcode    string
+		country     string
+		warn        bool
+		expPostcode string
+		expWarnings []Warning
+		expErr      error
+	}{
+		{desc: "UK", postcode: "w1a 0ax", country: "GB", expPostcode: "W1A0AX"},
+		{desc: "Canada", postcode: "K1A 0B1", country: "CA", expPostcode: "K1A0B1"},
+		{desc: "Netherlands", postcode: "1234 ab", country: "NL", expPostcode: "1234AB"},
+		{desc: "country prefix", postcode: "LT-01001", country: "LT", expPostcode: "01001 "},
+		{desc: "short", postcode: "1010", country: "AT", expPostcode: "1010  "},
+		{desc: "empty", postcode: "", country: "HK", expPostcode: "      "},
+		{
+			desc:        "truncated",
+			postcode:    "SW1A 1AA",
+			country:     "GB",
+			warn:        true,
+			expPostcode: "SW1A1A",
+			expWarnings: []Warning{{Field: FieldPostcode, Offset: 17, Message: `postcode "SW1A 1AA" truncated to "SW1A1A"`}},
+		},
+		{
+			desc:     "too long",
+			postcode: "SW1A 1AA9",
+			country:  "GB",
+			expErr:   ErrInvalidPostcode,
+		},
+		{
+			desc:     "invalid character",
+			postcode: "SW1A!A",
+			country:  "GB",
+			expErr:   ErrInvalidPostcode,
+		},
+		{
+			desc:        "replaced characters",
+			postcode:    "ÅB{12",
+			country:     "SE",
+			warn:        true,
+			expPostcode: " B 12 ",
+			expWarnings: []Warning{
+				{Field: FieldPostcode, Offset: 10, Message: `'Å' at offset 10 is not a valid postcode character, replaced with a space`},
+				{Field: FieldPostcode, Offset: 13, Message: `'{' at offset 13 is not a valid postcode character, replaced with a space`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			country, err := LookupCountry(tc.country)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var warnings []Warning
+
+			var warn func(Warning)
+			if tc.warn {
+				warn = func(w Warning) { warnings = append(warnings, w) }
+			}
+
+			postcode, err := mode3Postcode(tc.postcode, country, 10, tc.warn, warn)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected %v, got %v", tc.expErr, err)
+			}
+
+			if postcode != tc.expPostcode {
+				t.Errorf("expected %q, got %q", tc.expPostcode, postcode)
+			}
+
+			if diff := cmp.Diff(tc.expWarnings, warnings); diff != "" {
+				t.Errorf("Warnings are not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEncodeMode3Postcode(t *testing.T) {
+	inputData := "[)>" + RS + "01" + GS + "96sw1a 1aa" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT
+
+	var warnings []Warning
+
+	encoder, err := NewEncoder(Mode3, WithWarnings(func(w Warning) { warnings = append(warnings, w) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grids, err := encoder.Encode(inputData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(warnings) != 1 || warnings[0].Offset != 16 {
+		t.Errorf("expected the truncation warning, got %v", warnings)
+	}
+
+	result, err := Decode(grids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expData := "[)>" + RS + "01" + GS + "96SW1A1A" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT
+	if result.Data != expData {
+		t.Errorf("expected %q, got %q", expData, result.Data)
+	}
+}
+
+func TestEncodeMode3PostcodeTooLong(t *testing.T) {
+	inputData := "[)>" + RS + "01" + GS + "96SW1A 1AA9" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + RS + EOT
+
+	_, err := Encode(3, 0, inputData)
+
+	var encodeErr *EncodeError
+	if !errors.Is(err, ErrInvalidPostcode) || !errors.As(err, &encodeErr) {
+		t.Fatalf("expected %v, got %v", ErrInvalidPostcode, err)
+	}
+
+	if encodeErr.Field != FieldPostcode || encodeErr.Offset != 16 {
+		t.Errorf("expected %s at offset 16, got %s at offset %d", FieldPostcode, encodeErr.Field, encodeErr.Offset)
+	}
+}
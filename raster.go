@@ -0,0 +1,236 @@
+package maxicode
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"slices"
+)
+
+// affine maps millimetres in the symbol to pixels:
+// x' = a*x + b*y + c, y' = d*x + e*y + f.
+type affine struct {
+	a, b, c float64
+	d, e, f float64
+}
+
+func (t affine) apply(p point) point {
+	return point{t.a*p.x + t.b*p.y + t.c, t.d*p.x + t.e*p.y + t.f}
+}
+
+// placement scales the symbol to dpmm and rotates it clockwise by degrees,
+// moving it so the bounding box of the rotated symbol starts at x, y.
+func placement(dpmm, degrees, x, y float64) affine {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	t := affine{a: cos * dpmm, b: -sin * dpmm, d: sin * dpmm, e: cos * dpmm}
+
+	left, top := math.Inf(1), math.Inf(1)
+	for _, p := range []point{{0, 0}, {symbolWidth, 0}, {0, symbolHeight}, {symbolWidth, symbolHeight}} {
+		q := t.apply(p)
+		left, top = min(left, q.x), min(top, q.y)
+	}
+
+	t.c, t.f = x-left, y-top
+
+	return t
+}
+
+// scale is how many pixels one millimetre covers, the same in every
+// direction as the symbol is only scaled, rotated and moved.
+func (t affine) scale() float64 {
+	return math.Hypot(t.a, t.d)
+}
+
+// fillSymbol calls fill for every run of pixels in clip whose centres lie
+// inside the bullseye rings or a module, fill(y, x0, x1) covering x0 up to
+// but not including x1.
+func (s *SymbolGrid) fillSymbol(t affine, clip image.Rectangle, fill func(y, x0, x1 int)) {
+	center := t.apply(point{bullseyeX, bullseyeY})
+	scale := t.scale()
+
+	for _, r := range bullseyeRadii {
+		fillRing(center, (r-bullseyeLineWidth/2)*scale, (r+bullseyeLineWidth/2)*scale, clip, fill)
+	}
+
+	var corners [6]point
+	for row := range 33 {
+		for column := range 30 {
+			if !s.GetModule(row, column) {
+				continue
+			}
+
+			for i, p := range hexagon(row, column) {
+				corners[i] = t.apply(p)
+			}
+
+			fillPolygon(corners[:], clip, fill)
+		}
+	}
+}
+
+// fillPolygon fills a polygon one scanline at a time, between pairs of the
+// points where the edges cross the centre of the row of pixels.
+func fillPolygon(corners []point, clip image.Rectangle, fill func(y, x0, x1 int)) {
+	top, bottom := corners[0].y, corners[0].y
+	for _, p := range corners[1:] {
+		top, bottom = min(top, p.y), max(bottom, p.y)
+	}
+
+	var crossings []float64
+
+	for y := max(clip.Min.Y, pixelIndex(top)); y < min(clip.Max.Y, pixelIndex(bottom)+1); y++ {
+		yc := float64(y) + 0.5
+		crossings = crossings[:0]
+
+		for i, p := range corners {
+			q := corners[(i+1)%len(corners)]
+			if (p.y <= yc) != (q.y <= yc) {
+				crossings = append(crossings, p.x+(yc-p.y)*(q.x-p.x)/(q.y-p.y))
+			}
+		}
+
+		slices.Sort(crossings)
+
+		for i := 0; i+1 < len(crossings); i += 2 {
+			fillSpan(y, crossings[i], crossings[i+1], clip, fill)
+		}
+	}
+}
+
+// fillRing fills the pixels whose centres lie between radius inner and outer
+// around center.
+func fillRing(center point, inner, outer float64, clip image.Rectangle, fill func(y, x0, x1 int)) {
+	for y := max(clip.Min.Y, pixelIndex(center.y-outer)); y < min(clip.Max.Y, pixelIndex(center.y+outer)+1); y++ {
+		dy := float64(y) + 0.5 - center.y
+		if math.Abs(dy) >= outer {
+			continue
+		}
+
+		xo := math.Sqrt(outer*outer - dy*dy)
+		if math.Abs(dy) >= inner {
+			fillSpan(y, center.x-xo, center.x+xo, clip, fill)
+			continue
+		}
+
+		xi := math.Sqrt(inner*inner - dy*dy)
+		fillSpan(y, center.x-xo, center.x-xi, clip, fill)
+		fillSpan(y, center.x+xi, center.x+xo, clip, fill)
+	}
+}
+
+// fillSpan fills the pixels of row y whose centres lie in [left, right).
+func fillSpan(y int, left, right float64, clip image.Rectangle, fill func(y, x0, x1 int)) {
+	x0 := max(clip.Min.X, int(math.Ceil(left-0.5)))
+	x1 := min(clip.Max.X, int(math.Ceil(right-0.5)))
+
+	if x0 < x1 {
+		fill(y, x0, x1)
+	}
+}
+
+// pixelIndex returns the pixel that coordinate v falls into.
+func pixelIndex(v float64) int {
+	return int(math.Floor(v))
+}
+
+// Render draws the symbol in black on white without antialiasing, at dpmm
+// pixels per millimetre and the same size and geometry as Draw. Pixels are
+// black when their centre lies inside a module or a bullseye ring, so the
+// output only depends on the symbol and dpmm.
+func (s *SymbolGrid) Render(dpmm float64) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, int(symbolWidth*dpmm), int(symbolHeight*dpmm)))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	s.fillSymbol(affine{a: dpmm, e: dpmm}, img.Rect, func(y, x0, x1 int) {
+		clear(img.Pix[img.PixOffset(x0, y):img.PixOffset(x1, y)])
+	})
+
+	return img
+}
+
+// RenderPaletted is Render on a transparent background, like Draw, with
+// black at palette index 1.
+func (s *SymbolGrid) RenderPaletted(dpmm float64) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, int(symbolWidth*dpmm), int(symbolHeight*dpmm)), color.Palette{color.Transparent, color.Black})
+
+	s.fillSymbol(affine{a: dpmm, e: dpmm}, img.Rect, func(y, x0, x1 int) {
+		row := img.Pix[img.PixOffset(x0, y):img.PixOffset(x1, y)]
+		for i := range row {
+			row[i] = 1
+		}
+	})
+
+	return img
+}
+
+// DrawInto draws the symbol in black into dst like Render, rotated clockwise
+// by rotation degrees, with the top left corner of the bounding box of the
+// rotated symbol at origin. Pixels outside the modules and rings are left as
+// they are.
+func (s *SymbolGrid) DrawInto(dst draw.Image, origin image.Point, dpmm, rotation float64) {
+	fill := spanFiller(dst)
+	clip := dst.Bounds()
+
+	clipped := func(y, x0, x1 int) {
+		x0, x1 = max(x0, clip.Min.X), min(x1, clip.Max.X)
+		if y >= clip.Min.Y && y < clip.Max.Y && x0 < x1 {
+			fill(y, x0, x1)
+		}
+	}
+
+	turn := math.Mod(math.Mod(rotation, 360)+360, 360)
+	if turn != 0 && turn != 90 && turn != 180 && turn != 270 {
+		t := placement(dpmm, rotation, float64(origin.X), float64(origin.Y))
+		s.fillSymbol(t, clip, fill)
+		return
+	}
+
+	// Quarter turns move the pixels of Render as they are, instead of
+	// sampling the rotated shapes where rounding decides pixels on an edge.
+	w, h := int(symbolWidth*dpmm), int(symbolHeight*dpmm)
+	ox, oy := origin.X, origin.Y
+
+	s.fillSymbol(affine{a: dpmm, e: dpmm}, image.Rect(0, 0, w, h), func(y, x0, x1 int) {
+		switch turn {
+		case 0:
+			clipped(oy+y, ox+x0, ox+x1)
+		case 180:
+			clipped(oy+h-1-y, ox+w-x1, ox+w-x0)
+		case 90:
+			for x := x0; x < x1; x++ {
+				clipped(oy+x, ox+h-1-y, ox+h-y)
+			}
+		case 270:
+			for x := x0; x < x1; x++ {
+				clipped(oy+w-1-x, ox+y, ox+y+1)
+			}
+		}
+	})
+}
+
+// spanFiller returns a function that paints runs of pixels of dst black,
+// writing the pixels directly for the image types Render and gg use.
+func spanFiller(dst draw.Image) func(y, x0, x1 int) {
+	switch img := dst.(type) {
+	case *image.Gray:
+		return func(y, x0, x1 int) {
+			clear(img.Pix[img.PixOffset(x0, y):img.PixOffset(x1, y)])
+		}
+	case *image.RGBA:
+		return func(y, x0, x1 int) {
+			row := img.Pix[img.PixOffset(x0, y):img.PixOffset(x1, y)]
+			for i := 0; i < len(row); i += 4 {
+				row[i], row[i+1], row[i+2], row[i+3] = 0, 0, 0, 0xff
+			}
+		}
+	default:
+		return func(y, x0, x1 int) {
+			for x := x0; x < x1; x++ {
+				dst.Set(x, y, color.Black)
+			}
+		}
+	}
+}
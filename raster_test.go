@@ -0,0 +1,227 @@
+package maxicode
+
+import (
+	"image"
+	"image/draw"
+	"testing"
+
+	"github.com/ingridhq/gg"
+)
+
+func TestRender(t *testing.T) {
+	grid := testGrid(t)
+
+	for _, dpmm := range []float64{4, 8, 12.5} {
+		img := grid.Render(dpmm)
+		drawn := grid.Draw(dpmm).Image()
+
+		if img.Bounds() != drawn.Bounds() {
+			t.Fatalf("%v dpmm: expected bounds %v, got %v", dpmm, drawn.Bounds(), img.Bounds())
+		}
+
+		// Only antialiased edge pixels may differ from Draw.
+		differ := 0
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+				_, _, _, a := drawn.At(x, y).RGBA()
+				if (a > 0x8000) != (img.GrayAt(x, y).Y == 0) {
+					differ++
+				}
+			}
+		}
+
+		if total := img.Rect.Dx() * img.Rect.Dy(); differ > total/50 {
+			t.Errorf("%v dpmm: %d of %d pixels differ from Draw", dpmm, differ, total)
+		}
+
+		paletted := grid.RenderPaletted(dpmm)
+		for i, v := range img.Pix {
+			if (v == 0) != (paletted.Pix[i] == 1) {
+				t.Fatalf("%v dpmm: paletted image differs at pixel %d", dpmm, i)
+			}
+		}
+
+		results, err := ScanImage(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 1 || results[0].Mode != 3 {
+			t.Errorf("%v dpmm: expected a mode 3 symbol, got %d results", dpmm, len(results))
+		}
+	}
+}
+
+func TestFillPolygon(t *testing.T) {
+	// Pixels are filled when their centre is inside the polygon, so a square
+	// from 1 to 3 covers two pixels in both directions.
+	testCases := []struct {
+		desc    string
+		corners []point
+		exp     [][3]int
+	}{
+		{
+			desc:    "square",
+			corners: []point{{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+			exp:     [][3]int{{1, 1, 3}, {2, 1, 3}},
+		},
+		{
+			desc:    "triangle",
+			corners: []point{{0, 0}, {4, 0}, {0, 4}},
+			exp:     [][3]int{{0, 0, 3}, {1, 0, 2}, {2, 0, 1}},
+		},
+		{
+			desc:    "clipped",
+			corners: []point{{-2, -2}, {9, -2}, {9, 9}, {-2, 9}},
+			exp:     [][3]int{{0, 0, 5}, {1, 0, 5}, {2, 0, 5}, {3, 0, 5}, {4, 0, 5}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var spans [][3]int
+			fillPolygon(tc.corners, image.Rect(0, 0, 5, 5), func(y, x0, x1 int) {
+				spans = append(spans, [3]int{y, x0, x1})
+			})
+
+			if len(spans) != len(tc.exp) {
+				t.Fatalf("expected spans %v, got %v", tc.exp, spans)
+			}
+
+			for i := range spans {
+				if spans[i] != tc.exp[i] {
+					t.Fatalf("expected spans %v, got %v", tc.exp, spans)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	grid, err := Encode(4, 0, "BENCHMARK")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		grid.Render(12)
+	}
+}
+
+func BenchmarkDraw(b *testing.B) {
+	grid, err := Encode(4, 0, "BENCHMARK")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		grid.Draw(12)
+	}
+}
+
+func TestDrawInto(t *testing.T) {
+	grid := testGrid(t)
+	render := grid.Render(10)
+	w, h := render.Rect.Dx(), render.Rect.Dy()
+
+	// Quarter turns map the pixels of Render onto each other.
+	testCases := []struct {
+		rotation float64
+		size     image.Point
+		source   func(x, y int) (int, int)
+	}{
+		{0, image.Pt(w, h), func(x, y int) (int, int) { return x, y }},
+		{90, image.Pt(h, w), func(x, y int) (int, int) { return y, h - 1 - x }},
+		{180, image.Pt(w, h), func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }},
+		{-90, image.Pt(h, w), func(x, y int) (int, int) { return w - 1 - y, x }},
+	}
+
+	for _, tc := range testCases {
+		origin := image.Pt(7, 3)
+
+		dst := image.NewGray(image.Rectangle{Max: origin.Add(tc.size).Add(image.Pt(5, 5))})
+		for i := range dst.Pix {
+			dst.Pix[i] = 0xff
+		}
+
+		grid.DrawInto(dst, origin, 10, tc.rotation)
+
+		for y := dst.Rect.Min.Y; y < dst.Rect.Max.Y; y++ {
+			for x := dst.Rect.Min.X; x < dst.Rect.Max.X; x++ {
+				var exp uint8 = 0xff
+				if p := image.Pt(x, y).Sub(origin); p.In(image.Rectangle{Max: tc.size}) {
+					exp = render.GrayAt(tc.source(p.X, p.Y)).Y
+				}
+
+				if got := dst.GrayAt(x, y).Y; got != exp {
+					t.Fatalf("rotation %v: expected %d at %d,%d, got %d", tc.rotation, exp, x, y, got)
+				}
+			}
+		}
+	}
+
+	// Any other angle still scans, and image types without a fast path
+	// get the same pixels.
+	gray := image.NewGray(image.Rect(0, 0, 400, 400))
+	for i := range gray.Pix {
+		gray.Pix[i] = 0xff
+	}
+
+	generic := image.NewNRGBA(gray.Rect)
+	draw.Draw(generic, generic.Rect, image.White, image.Point{}, draw.Src)
+
+	grid.DrawInto(gray, image.Pt(20, 20), 10, 30)
+	grid.DrawInto(generic, image.Pt(20, 20), 10, 30)
+
+	for i, v := range gray.Pix {
+		if generic.Pix[4*i] != v {
+			t.Fatalf("image types differ at pixel %d", i)
+		}
+	}
+
+	results, err := ScanImage(gray)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || results[0].Mode != 3 {
+		t.Errorf("expected a mode 3 symbol, got %d results", len(results))
+	}
+}
+
+func TestDrawIntoContext(t *testing.T) {
+	grid := testGrid(t)
+
+	dc := gg.NewContext(400, 400)
+	grid.DrawIntoContext(dc, 20, 20, 10, 90)
+
+	if x, y := dc.TransformPoint(1, 1); x != 1 || y != 1 {
+		t.Errorf("expected the transformation to be restored, got %v,%v", x, y)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, 400, 400))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	grid.DrawInto(img, image.Pt(20, 20), 10, 90)
+
+	// Only antialiased edge pixels may differ from DrawInto.
+	differ, total := 0, 0
+	for y := range 400 {
+		for x := range 400 {
+			_, _, _, a := dc.Image().At(x, y).RGBA()
+			if (a > 0x8000) != (img.GrayAt(x, y).Y == 0) {
+				differ++
+			}
+
+			if a > 0 {
+				total++
+			}
+		}
+	}
+
+	if differ > total/50 {
+		t.Errorf("%d of %d pixels differ from DrawInto", differ, total)
+	}
+}
@@ -0,0 +1,264 @@
+package readsolomon
+
+import "errors"
+
+var (
+	ErrTooManyErrors  = errors.New("readsolomon: too many errors to correct")
+	ErrInvalidErasure = errors.New("readsolomon: erasure position out of range")
+	ErrBlockTooShort  = errors.New("readsolomon: block is shorter than the error correction symbols")
+	ErrBlockTooLong   = errors.New("readsolomon: block is longer than the field allows")
+	ErrInvalidSymbol  = errors.New("readsolomon: symbol is outside the field")
+)
+
+// Decoder corrects blocks produced by an Encoder created with the same
+// polynomial, number of error correction symbols and first root index.
+type Decoder struct {
+	logSize    int
+	eccSymbols int
+	index      int
+	logTable   []int
+	alogTable  []int
+}
+
+func NewDecoder(polynomial, eccSymbols, index int) *Decoder {
+	logSize, logTable, alogTable := buildTables(polynomial)
+
+	return &Decoder{
+		logSize:    logSize,
+		eccSymbols: eccSymbols,
+		index:      index,
+		logTable:   logTable,
+		alogTable:  alogTable,
+	}
+}
+
+// Decode corrects block in place. The block holds the data symbols followed
+// by the error correction symbols, in the order Encode emits them. Erasures
+// are positions in block that are known to be unreliable; each of them costs
+// one error correction symbol instead of two.
+//
+// It returns the number of symbols that were changed.
+func (d *Decoder) Decode(block []byte, erasures []int) (int, error) {
+	n := len(block)
+
+	if n < d.eccSymbols {
+		return 0, ErrBlockTooShort
+	}
+
+	if n > d.logSize {
+		return 0, ErrBlockTooLong
+	}
+
+	for _, c := range block {
+		if int(c) > d.logSize {
+			return 0, ErrInvalidSymbol
+		}
+	}
+
+	for _, pos := range erasures {
+		if pos < 0 || pos >= n {
+			return 0, ErrInvalidErasure
+		}
+	}
+
+	if len(erasures) > d.eccSymbols {
+		return 0, ErrTooManyErrors
+	}
+
+	syndromes := d.Syndromes(block)
+
+	clean := true
+	for _, s := range syndromes {
+		if s != 0 {
+			clean = false
+			break
+		}
+	}
+
+	if clean {
+		return 0, nil
+	}
+
+	// Errata locator, seeded with the known erasures (Berlekamp-Massey).
+	locator := []int{1}
+	for _, pos := range erasures {
+		locator = d.polyMul(locator, []int{1, d.alpha(n - 1 - pos)})
+	}
+
+	previous := append([]int(nil), locator...)
+	erased := len(erasures)
+	length := erased
+
+	for r := erased + 1; r <= d.eccSymbols; r++ {
+		delta := 0
+		for i := 0; i <= length && i < len(locator); i++ {
+			if r-1-i >= 0 {
+				delta ^= d.mul(locator[i], syndromes[r-1-i])
+			}
+		}
+
+		shifted := append([]int{0}, previous...)
+
+		if delta == 0 {
+			previous = shifted
+			continue
+		}
+
+		next := d.polyAdd(locator, d.polyScale(shifted, delta))
+
+		if 2*length <= r+erased-1 {
+			previous = d.polyScale(locator, d.inv(delta))
+			length = r + erased - length
+		} else {
+			previous = shifted
+		}
+
+		locator = next
+	}
+
+	locator = trim(locator)
+
+	if len(locator)-1 != length || 2*(length-erased)+erased > d.eccSymbols {
+		return 0, ErrTooManyErrors
+	}
+
+	// Chien search for the error positions.
+	var positions []int
+	for pos := 0; pos < n; pos++ {
+		if d.polyEval(locator, d.alpha(-(n-1-pos))) == 0 {
+			positions = append(positions, pos)
+		}
+	}
+
+	if len(positions) != length {
+		return 0, ErrTooManyErrors
+	}
+
+	// Forney algorithm for the error values.
+	evaluator := d.polyMul(syndromes, locator)
+	if len(evaluator) > d.eccSymbols {
+		evaluator = evaluator[:d.eccSymbols]
+	}
+
+	derivative := make([]int, len(locator))
+	for i := 1; i < len(locator); i += 2 {
+		derivative[i-1] = locator[i]
+	}
+
+	corrected := 0
+	for _, pos := range positions {
+		power := n - 1 - pos
+		xInv := d.alpha(-power)
+
+		denominator := d.polyEval(derivative, xInv)
+		if denominator == 0 {
+			return 0, ErrTooManyErrors
+		}
+
+		magnitude := d.mul(d.polyEval(evaluator, xInv), d.inv(denominator))
+		magnitude = d.mul(magnitude, d.alpha(power*(1-d.index)))
+
+		if magnitude != 0 {
+			block[pos] ^= byte(magnitude)
+			corrected++
+		}
+	}
+
+	for _, s := range d.Syndromes(block) {
+		if s != 0 {
+			return 0, ErrTooManyErrors
+		}
+	}
+
+	return corrected, nil
+}
+
+// Syndromes evaluates block at each root of the generator polynomial. All
+// syndromes are zero when the block holds no errors. Every symbol of block
+// must be an element of the field.
+func (d *Decoder) Syndromes(block []byte) []int {
+	syndromes := make([]int, d.eccSymbols)
+
+	for j := range syndromes {
+		root := d.alpha(d.index + j)
+
+		s := 0
+		for _, c := range block {
+			s = d.mul(s, root) ^ int(c)
+		}
+
+		syndromes[j] = s
+	}
+
+	return syndromes
+}
+
+func (d *Decoder) alpha(power int) int {
+	power %= d.logSize
+	if power < 0 {
+		power += d.logSize
+	}
+
+	return d.alogTable[power]
+}
+
+func (d *Decoder) mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return d.alogTable[(d.logTable[a]+d.logTable[b])%d.logSize]
+}
+
+func (d *Decoder) inv(a int) int {
+	return d.alogTable[(d.logSize-d.logTable[a])%d.logSize]
+}
+
+// Polynomials are stored lowest degree first.
+
+func (d *Decoder) polyEval(p []int, x int) int {
+	y := 0
+	for i := len(p) - 1; i >= 0; i-- {
+		y = d.mul(y, x) ^ p[i]
+	}
+
+	return y
+}
+
+func (d *Decoder) polyMul(a, b []int) []int {
+	product := make([]int, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			product[i+j] ^= d.mul(x, y)
+		}
+	}
+
+	return product
+}
+
+func (d *Decoder) polyScale(p []int, x int) []int {
+	scaled := make([]int, len(p))
+	for i, c := range p {
+		scaled[i] = d.mul(c, x)
+	}
+
+	return scaled
+}
+
+func (d *Decoder) polyAdd(a, b []int) []int {
+	sum := make([]int, max(len(a), len(b)))
+	copy(sum, a)
+	for i, c := range b {
+		sum[i] ^= c
+	}
+
+	return sum
+}
+
+func trim(p []int) []int {
+	for len(p) > 1 && p[len(p)-1] == 0 {
+		p = p[:len(p)-1]
+	}
+
+	return p
+}
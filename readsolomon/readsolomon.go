@@ -9,32 +9,9 @@ type Encoder struct {
 }
 
 func NewEncoder(polynomial, eccSymbols, index int) *Encoder {
-	size := 0
-
-	// Find the top bit, and hence the symbol size
-	b := 1
-	for ; b <= polynomial; b <<= 1 {
-		size++
-	}
-
-	size--
-	b >>= 1
-
-	// Build the log and antilog tables.
-	logSize := (1 << uint(size)) - 1
-	logTable := make([]int, logSize+1)
-	alogTable := make([]int, logSize)
+	logSize, logTable, alogTable := buildTables(polynomial)
 	rsPolynomial := make([]int, eccSymbols+1)
 
-	for p, v := 1, 0; v < logSize; v++ {
-		alogTable[v] = p
-		logTable[p] = v
-		p <<= 1
-		if (p & b) != 0 {
-			p ^= polynomial
-		}
-	}
-
 	rsPolynomial[0] = 1
 	for i := 1; i <= eccSymbols; i++ {
 		rsPolynomial[i] = 1
@@ -78,3 +55,32 @@ func (e *Encoder) Encode(length int, data, ecc []byte) {
 		}
 	}
 }
+
+func buildTables(polynomial int) (int, []int, []int) {
+	size := 0
+
+	// Find the top bit, and hence the symbol size
+	b := 1
+	for ; b <= polynomial; b <<= 1 {
+		size++
+	}
+
+	size--
+	b >>= 1
+
+	// Build the log and antilog tables.
+	logSize := (1 << uint(size)) - 1
+	logTable := make([]int, logSize+1)
+	alogTable := make([]int, logSize)
+
+	for p, v := 1, 0; v < logSize; v++ {
+		alogTable[v] = p
+		logTable[p] = v
+		p <<= 1
+		if (p & b) != 0 {
+			p ^= polynomial
+		}
+	}
+
+	return logSize, logTable, alogTable
+}
@@ -0,0 +1,112 @@
+package readsolomon
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func encodeBlock(e *Encoder, data []byte) []byte {
+	ecc := make([]byte, e.eccSymbols)
+	e.Encode(len(data), data, ecc)
+
+	block := slices.Clone(data)
+	for j := range ecc {
+		block = append(block, ecc[len(ecc)-1-j])
+	}
+
+	return block
+}
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		dataLen   int
+		ecc       int
+		errors    int
+		erasures  int
+		expectErr error
+	}{
+		{desc: "no errors", dataLen: 10, ecc: 10},
+		{desc: "primary message, max errors", dataLen: 10, ecc: 10, errors: 5},
+		{desc: "primary message, max erasures", dataLen: 10, ecc: 10, erasures: 10},
+		{desc: "secondary message, errors and erasures", dataLen: 42, ecc: 20, errors: 6, erasures: 8},
+		{desc: "enhanced secondary message, max errors", dataLen: 34, ecc: 28, errors: 14},
+		{desc: "too many errors", dataLen: 42, ecc: 20, errors: 11, expectErr: ErrTooManyErrors},
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			encoder := NewEncoder(0x43, tc.ecc, 1)
+			decoder := NewDecoder(0x43, tc.ecc, 1)
+
+			for range 100 {
+				data := make([]byte, tc.dataLen)
+				for i := range data {
+					data[i] = byte(r.Intn(64))
+				}
+
+				block := encodeBlock(encoder, data)
+				expected := slices.Clone(block)
+
+				positions := r.Perm(len(block))[:tc.errors+tc.erasures]
+				for _, pos := range positions {
+					block[pos] ^= byte(1 + r.Intn(63))
+				}
+
+				corrected, err := decoder.Decode(block, positions[tc.errors:])
+				if tc.expectErr != nil {
+					// Too many errors may still decode to another valid codeword.
+					if err == nil && slices.Equal(block, expected) {
+						t.Fatal("expected decoding to fail")
+					}
+
+					if err != nil && !errors.Is(err, tc.expectErr) {
+						t.Fatalf("expected %v, got %v", tc.expectErr, err)
+					}
+
+					continue
+				}
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if corrected != tc.errors+tc.erasures {
+					t.Errorf("expected %d corrected symbols, got %d", tc.errors+tc.erasures, corrected)
+				}
+
+				if !slices.Equal(block, expected) {
+					t.Fatalf("block was not corrected:\n got %v\nwant %v", block, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidInput(t *testing.T) {
+	decoder := NewDecoder(0x43, 10, 1)
+
+	testCases := []struct {
+		desc      string
+		block     []byte
+		erasures  []int
+		expectErr error
+	}{
+		{desc: "symbol outside the field", block: append(make([]byte, 19), 64), expectErr: ErrInvalidSymbol},
+		{desc: "erasure out of range", block: make([]byte, 20), erasures: []int{20}, expectErr: ErrInvalidErasure},
+		{desc: "block too short", block: make([]byte, 9), expectErr: ErrBlockTooShort},
+		{desc: "block too long", block: make([]byte, 64), expectErr: ErrBlockTooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := decoder.Decode(tc.block, tc.erasures); !errors.Is(err, tc.expectErr) {
+				t.Fatalf("expected %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
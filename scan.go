@@ -0,0 +1,557 @@
+package maxicode
+
+import (
+	"cmp"
+	"errors"
+	"image"
+	"math"
+	"slices"
+)
+
+// ErrNotFound is returned by ScanImage when the image holds no symbol that
+// can be decoded.
+var ErrNotFound = errors.New("no MaxiCode symbol found")
+
+// Bullseye geometry as drawn by SymbolGrid.Draw, in millimetres from the centre.
+// The edges are listed from the inside out, starting with the inner edge of the inner ring.
+var bullseyeEdges = []float64{
+	bullseyeRadii[2] - bullseyeLineWidth/2, bullseyeRadii[2] + bullseyeLineWidth/2,
+	bullseyeRadii[1] - bullseyeLineWidth/2, bullseyeRadii[1] + bullseyeLineWidth/2,
+	bullseyeRadii[0] - bullseyeLineWidth/2,
+}
+
+// Orientation clusters surrounding the bullseye (row, column).
+var (
+	orientationDark = [][2]int{
+		{9, 10}, {9, 11}, {10, 11},
+		{15, 7}, {16, 8},
+		{16, 20}, {17, 20},
+		{22, 10}, {23, 10},
+		{22, 17}, {23, 17},
+	}
+	orientationLight = [][2]int{
+		{9, 17}, {10, 17}, {10, 18},
+		{16, 7}, {16, 21}, {22, 11}, {23, 16},
+	}
+)
+
+// ScanImage finds and decodes every MaxiCode symbol in img. Symbols may be
+// scaled and rotated by any angle.
+func ScanImage(img image.Image) ([]Result, error) {
+	s := newScanner(img)
+
+	var results []Result
+
+	for _, f := range s.findBullseyes() {
+		result, err := s.read(f)
+		if err != nil {
+			continue
+		}
+
+		results = append(results, *result)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return results, nil
+}
+
+type scanner struct {
+	width, height int
+	gray          []float64
+	threshold     float64
+}
+
+// bullseye is a candidate symbol centre (pixels) and its scale (pixels per millimetre).
+type bullseye struct {
+	x, y  float64
+	scale float64
+}
+
+func newScanner(img image.Image) *scanner {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	lum := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+
+			// Transparent pixels are treated as white paper.
+			bg := float64(0xffff - a)
+			lum[y*w+x] = (0.299*(float64(r)+bg) + 0.587*(float64(g)+bg) + 0.114*(float64(b)+bg)) / 257
+		}
+	}
+
+	// A small gaussian blur takes care of speckle noise before binarization.
+	gray := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sum, weight := 0.0, 0.0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if xx, yy := x+dx, y+dy; xx >= 0 && xx < w && yy >= 0 && yy < h {
+						k := float64(int(4) >> (abs(dx) + abs(dy)))
+						sum += k * lum[yy*w+xx]
+						weight += k
+					}
+				}
+			}
+
+			gray[y*w+x] = sum / weight
+		}
+	}
+
+	return &scanner{
+		width:     w,
+		height:    h,
+		gray:      gray,
+		threshold: otsu(lum),
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// otsu returns the threshold that best separates values into dark and light.
+func otsu(values []float64) float64 {
+	var hist [256]int
+	for _, v := range values {
+		hist[min(max(int(v), 0), 255)]++
+	}
+
+	total := len(values)
+	sum := 0.0
+	for i, n := range hist {
+		sum += float64(i * n)
+	}
+
+	best, threshold := -1.0, 128.0
+	sumB, weightB := 0.0, 0
+
+	for i, n := range hist {
+		weightB += n
+		if weightB == 0 {
+			continue
+		}
+
+		weightF := total - weightB
+		if weightF == 0 {
+			break
+		}
+
+		sumB += float64(i * n)
+		meanB := sumB / float64(weightB)
+		meanF := (sum - sumB) / float64(weightF)
+
+		between := float64(weightB) * float64(weightF) * (meanB - meanF) * (meanB - meanF)
+		if between > best {
+			best = between
+			threshold = float64(i) + 0.5
+		}
+	}
+
+	// Antialiased edges fill the valley between dark and light, so settle
+	// halfway between the most common dark and light levels.
+	dark, light := 0, 255
+	for i, n := range hist {
+		if float64(i) < threshold && n > hist[dark] {
+			dark = i
+		}
+
+		if float64(i) > threshold && n > hist[light] {
+			light = i
+		}
+	}
+
+	return float64(dark+light) / 2
+}
+
+func (s *scanner) dark(x, y int) bool {
+	return s.gray[y*s.width+x] < s.threshold
+}
+
+// sample returns the bilinear interpolated grey level at (x, y).
+func (s *scanner) sample(x, y float64) float64 {
+	x, y = x-0.5, y-0.5
+
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	at := func(x, y int) float64 {
+		if x < 0 || y < 0 || x >= s.width || y >= s.height {
+			return 255
+		}
+
+		return s.gray[y*s.width+x]
+	}
+
+	top := at(x0, y0)*(1-fx) + at(x0+1, y0)*fx
+	bottom := at(x0, y0+1)*(1-fx) + at(x0+1, y0+1)*fx
+
+	return top*(1-fy) + bottom*fy
+}
+
+// findBullseyes scans every row for the light-dark run pattern of the rings
+// and confirms each hit in several directions.
+func (s *scanner) findBullseyes() []bullseye {
+	var found []bullseye
+
+	for y := 0; y < s.height; y++ {
+		var runs []int
+		start := 0
+
+		for x := 1; x <= s.width; x++ {
+			if x < s.width && s.dark(x, y) == s.dark(x-1, y) {
+				continue
+			}
+
+			runs = append(runs, start, x-start)
+			start = x
+		}
+
+		// runs holds (start, length) pairs of alternating colour.
+		for i := 0; i+22 <= len(runs); i += 2 {
+			if !s.dark(runs[i], y) || !matchesBullseye(runs[i+1:i+22:i+22]) {
+				continue
+			}
+
+			center := runs[i+10]
+			x := float64(center) + float64(runs[i+11])/2
+
+			if slices.ContainsFunc(found, func(b bullseye) bool {
+				return math.Hypot(b.x-x, b.y-float64(y)) < 2*b.scale
+			}) {
+				continue
+			}
+
+			if b, ok := s.confirmBullseye(x, float64(y)+0.5); ok {
+				found = append(found, b)
+			}
+		}
+	}
+
+	return found
+}
+
+// matchesBullseye checks the lengths of 11 alternating runs, the centre one
+// being the light spot in the middle of the inner ring.
+func matchesBullseye(runs []int) bool {
+	var lengths [11]float64
+	for i := range lengths {
+		lengths[i] = float64(runs[2*i])
+	}
+
+	// Rings and gaps are all about 0.67 mm wide.
+	unit := 0.0
+	for _, i := range []int{1, 2, 3, 4, 6, 7, 8, 9} {
+		unit += lengths[i]
+	}
+	unit /= 8
+
+	if unit < 1 {
+		return false
+	}
+
+	for _, i := range []int{1, 2, 3, 4, 6, 7, 8, 9} {
+		if lengths[i] < unit*0.5 || lengths[i] > unit*1.5 {
+			return false
+		}
+	}
+
+	// The outermost rings may run into neighbouring modules.
+	if lengths[0] < unit*0.5 || lengths[10] < unit*0.5 {
+		return false
+	}
+
+	return lengths[5] > unit*0.9 && lengths[5] < unit*2.5
+}
+
+// confirmBullseye refines the centre and measures the scale along sixteen
+// directions through the candidate centre.
+func (s *scanner) confirmBullseye(x, y float64) (bullseye, bool) {
+	// Re-centre horizontally and vertically.
+	for range 2 {
+		left, ok1 := s.edges(x, y, -1, 0)
+		right, ok2 := s.edges(x, y, 1, 0)
+		if !ok1 || !ok2 {
+			return bullseye{}, false
+		}
+
+		x += centreOffset(left, right)
+
+		up, ok1 := s.edges(x, y, 0, -1)
+		down, ok2 := s.edges(x, y, 0, 1)
+		if !ok1 || !ok2 {
+			return bullseye{}, false
+		}
+
+		y += centreOffset(up, down)
+	}
+
+	var pixels, mm float64
+
+	matched := 0
+	for i := range 16 {
+		angle := float64(i) * math.Pi / 8
+
+		edges, ok := s.edges(x, y, math.Cos(angle), math.Sin(angle))
+		if !ok {
+			continue
+		}
+
+		matched++
+		for k, e := range edges {
+			pixels += e
+			mm += bullseyeEdges[k]
+		}
+	}
+
+	if matched < 12 {
+		return bullseye{}, false
+	}
+
+	return bullseye{x: x, y: y, scale: pixels / mm}, true
+}
+
+// centreOffset is how far the centre lies towards after, given the ring edges
+// found on either side of it.
+func centreOffset(before, after []float64) float64 {
+	offset := 0.0
+	for k := range before {
+		offset += (after[k] - before[k]) / 2
+	}
+
+	return offset / float64(len(before))
+}
+
+// edges walks from the centre in direction (dx, dy) and returns the distance
+// to each ring edge, if they are spaced like the bullseye.
+func (s *scanner) edges(x, y, dx, dy float64) ([]float64, bool) {
+	var edges []float64
+
+	dark := func(t float64) (bool, bool) {
+		px, py := x+dx*t, y+dy*t
+		if px < 0 || py < 0 || px >= float64(s.width) || py >= float64(s.height) {
+			return false, false
+		}
+
+		return s.dark(int(px), int(py)), true
+	}
+
+	if d, ok := dark(0); !ok || d {
+		return nil, false
+	}
+
+	prev := false
+	for t := 0.5; len(edges) < len(bullseyeEdges); t += 0.5 {
+		d, ok := dark(t)
+		if !ok {
+			return nil, false
+		}
+
+		if d == prev {
+			continue
+		}
+
+		// Ignore single pixel specks.
+		if next, ok := dark(t + 1); !ok || next != d {
+			continue
+		}
+
+		edges = append(edges, t-0.25)
+		prev = d
+	}
+
+	outer := edges[len(edges)-1]
+	for k, e := range edges {
+		expected := bullseyeEdges[k] / bullseyeEdges[len(bullseyeEdges)-1]
+		if math.Abs(e/outer-expected) > 0.1 {
+			return nil, false
+		}
+	}
+
+	return edges, true
+}
+
+// symbolLayout maps module (row, column) to image pixels.
+type symbolLayout struct {
+	x, y     float64
+	scale    float64
+	cos, sin float64
+}
+
+func newSymbolLayout(x, y, scale, angle float64) symbolLayout {
+	return symbolLayout{x: x, y: y, scale: scale, cos: math.Cos(angle), sin: math.Sin(angle)}
+}
+
+func (l symbolLayout) module(row, column int) (float64, float64) {
+	// Centre of the hexagon relative to the bullseye, in millimetres.
+	hex := hexagon(row, column)
+	mx := hex[0].x - bullseyeX
+	my := (hex[0].y+hex[3].y)/2 - bullseyeY
+
+	return l.x + l.scale*(mx*l.cos-my*l.sin), l.y + l.scale*(mx*l.sin+my*l.cos)
+}
+
+func (s *scanner) sampleModule(l symbolLayout, row, column int) float64 {
+	return s.sample(l.module(row, column))
+}
+
+// orientationScore is high when the orientation clusters line up with layout l.
+func (s *scanner) orientationScore(l symbolLayout) float64 {
+	score := 0.0
+
+	for _, m := range orientationLight {
+		score += s.sampleModule(l, m[0], m[1])
+	}
+
+	for _, m := range orientationDark {
+		score -= s.sampleModule(l, m[0], m[1])
+	}
+
+	return score
+}
+
+// contrast is high when every data module is sampled near its centre.
+func (s *scanner) contrast(l symbolLayout) float64 {
+	sum := 0.0
+
+	for row := 0; row < 33; row++ {
+		for column := 0; column < 30; column++ {
+			if maxiGrid[(row*30)+column] != 0 {
+				sum += math.Abs(s.sampleModule(l, row, column) - s.threshold)
+			}
+		}
+	}
+
+	return sum
+}
+
+func (s *scanner) read(b bullseye) (*Result, error) {
+	type candidate struct {
+		angle float64
+		score float64
+	}
+
+	var candidates []candidate
+
+	for deg := 0; deg < 360; deg++ {
+		angle := float64(deg) * math.Pi / 180
+		candidates = append(candidates, candidate{
+			angle: angle,
+			score: s.orientationScore(newSymbolLayout(b.x, b.y, b.scale, angle)),
+		})
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	var tried []float64
+
+	for _, c := range candidates {
+		// Skip angles next to one already tried.
+		if slices.ContainsFunc(tried, func(a float64) bool {
+			d := math.Abs(math.Remainder(a-c.angle, 2*math.Pi))
+			return d < 10*math.Pi/180
+		}) {
+			continue
+		}
+
+		if len(tried) == 3 {
+			break
+		}
+
+		tried = append(tried, c.angle)
+
+		result, err := Decode(s.sampleGrid(s.align(b, c.angle)))
+		if err == nil {
+			return result, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// align fine tunes the angle, scale and centre of a symbol.
+func (s *scanner) align(b bullseye, angle float64) symbolLayout {
+	best := newSymbolLayout(b.x, b.y, b.scale, angle)
+	bestContrast := s.contrast(best)
+
+	// The orientation clusters only give a rough angle, sweep around it first.
+	for deg := -5.0; deg <= 5; deg += 0.5 {
+		l := newSymbolLayout(b.x, b.y, b.scale, angle+deg*math.Pi/180)
+		if c := s.contrast(l); c > bestContrast {
+			best, bestContrast = l, c
+		}
+	}
+
+	improved := false
+	search := func(try func(l symbolLayout) symbolLayout) {
+		l := try(best)
+		if c := s.contrast(l); c > bestContrast {
+			best, bestContrast = l, c
+			improved = true
+		}
+	}
+
+	step := struct{ angle, scale, offset float64 }{1 * math.Pi / 180, 0.02, 0.1 * b.scale}
+
+	for range 12 {
+		improved = false
+
+		for _, d := range []float64{-1, 1} {
+			search(func(l symbolLayout) symbolLayout {
+				a := math.Atan2(l.sin, l.cos) + d*step.angle
+				return newSymbolLayout(l.x, l.y, l.scale, a)
+			})
+			search(func(l symbolLayout) symbolLayout {
+				l.scale *= 1 + d*step.scale
+				return l
+			})
+			search(func(l symbolLayout) symbolLayout {
+				l.x += d * step.offset
+				return l
+			})
+			search(func(l symbolLayout) symbolLayout {
+				l.y += d * step.offset
+				return l
+			})
+		}
+
+		if !improved {
+			step.angle /= 2
+			step.scale /= 2
+			step.offset /= 2
+		}
+	}
+
+	return best
+}
+
+func (s *scanner) sampleGrid(l symbolLayout) *SymbolGrid {
+	samples := make([]float64, 0, 30*33)
+
+	for row := 0; row < 33; row++ {
+		for column := 0; column < 30; column++ {
+			samples = append(samples, s.sampleModule(l, row, column))
+		}
+	}
+
+	threshold := otsu(samples)
+
+	var grid SymbolGrid
+	for i, v := range samples {
+		grid[i] = v < threshold
+	}
+
+	return &grid
+}
@@ -0,0 +1,164 @@
+package maxicode
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// transform renders src rotated by angle degrees and scaled by factor onto
+// a white canvas with a margin, like a photo of a printed label.
+func transform(src image.Image, angle, factor float64) image.Image {
+	b := src.Bounds()
+	w, h := float64(b.Dx())*factor, float64(b.Dy())*factor
+	size := int(math.Hypot(w, h)) + 40
+
+	dst := image.NewGray(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	cx, cy := float64(size)/2, float64(size)/2
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			// Map back into the source image.
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			sx := (dx*cos+dy*sin)/factor + float64(b.Dx())/2
+			sy := (-dx*sin+dy*cos)/factor + float64(b.Dy())/2
+
+			ix, iy := int(math.Floor(sx)), int(math.Floor(sy))
+			if ix < 0 || iy < 0 || ix >= b.Dx() || iy >= b.Dy() {
+				continue
+			}
+
+			_, _, _, a := src.At(b.Min.X+ix, b.Min.Y+iy).RGBA()
+			dst.SetGray(x, y, color.Gray{Y: uint8(255 - a>>8)})
+		}
+	}
+
+	return dst
+}
+
+func addNoise(img image.Image, seed int64, amount float64, speckles float64) image.Image {
+	r := rand.New(rand.NewSource(seed))
+
+	b := img.Bounds()
+	dst := image.NewGray(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			v := float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+			v += r.NormFloat64() * amount
+
+			if r.Float64() < speckles {
+				v = float64(r.Intn(2) * 255)
+			}
+
+			dst.SetGray(x, y, color.Gray{Y: uint8(min(max(v, 0), 255))})
+		}
+	}
+
+	return dst
+}
+
+func TestScanImage(t *testing.T) {
+	inputData := "[)>" + RS + "01" + GS + "09651147" + GS + "276" + GS + "066" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "N" + GS + "5 WALDSTRASSE" + GS + "COLOGNE" + GS + "" + RS + "" + EOT
+
+	grid, err := Encode(3, 0, inputData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc string
+		img  image.Image
+	}{
+		{
+			desc: "as drawn",
+			img:  grid.Draw(8).Image(),
+		},
+		{
+			desc: "rotated 90 degrees",
+			img:  transform(grid.Draw(8).Image(), 90, 1),
+		},
+		{
+			desc: "rotated 33 degrees",
+			img:  transform(grid.Draw(8).Image(), 33, 1),
+		},
+		{
+			desc: "rotated 200 degrees and scaled down",
+			img:  transform(grid.Draw(10).Image(), 200, 0.55),
+		},
+		{
+			desc: "scaled up",
+			img:  transform(grid.Draw(6).Image(), 0, 2.5),
+		},
+		{
+			desc: "rotated with noise",
+			img:  addNoise(transform(grid.Draw(8).Image(), -47, 1), 1, 30, 0.02),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			results, err := ScanImage(tc.img)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != 1 {
+				t.Fatalf("expected 1 symbol, got %d", len(results))
+			}
+
+			if results[0].Mode != 3 || results[0].Data != inputData {
+				t.Errorf("unexpected result: mode %d, data %q", results[0].Mode, results[0].Data)
+			}
+		})
+	}
+}
+
+func TestScanImageMultipleSymbols(t *testing.T) {
+	inputs := []string{"FIRST SYMBOL", "SECOND SYMBOL"}
+
+	img := image.NewGray(image.Rect(0, 0, 520, 260))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for i, inputData := range inputs {
+		grid, err := Encode(4, 0, inputData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		symbol := grid.Draw(8).Image()
+		draw.Draw(img, symbol.Bounds().Add(image.Pt(10+i*260, 20)), symbol, image.Point{}, draw.Over)
+	}
+
+	results, err := ScanImage(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, result := range results {
+		got = append(got, result.Data)
+	}
+
+	if diff := cmp.Diff(inputs, got); diff != "" {
+		t.Errorf("Scanned symbols are not equal to expected, diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestScanImageNotFound(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 200, 200))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	if _, err := ScanImage(img); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package maxicode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SCM is a structured carrier message split into its fields.
+type SCM struct {
+	// Format of the envelope holding the UPS fields, always "01".
+	Format   string
+	Shipment UPSShipment
+
+	// Unknown holds the fields that follow the ship to state.
+	Unknown []string
+
+	// Trailing holds everything between the RS that closes the format 01
+	// envelope and the EOT, Envelopes the same split into format envelopes.
+	Trailing  string
+	Envelopes []Envelope
+}
+
+// scmFieldNames are the fields of a format 01 envelope in order.
+var scmFieldNames = []Field{
+	FieldPostcode,
+	FieldCountry,
+	FieldServiceClass,
+	FieldTrackingNumber,
+	FieldSCAC,
+	FieldShipperNumber,
+	FieldPickupDay,
+	FieldShipmentID,
+	FieldPackage,
+	FieldWeight,
+	FieldAddressValidation,
+	FieldShipToAddress,
+	FieldShipToCity,
+	FieldShipToState,
+}
+
+// ParseSCM splits a structured carrier message, as given to Encode in modes
+// 2 and 3, into its fields and format envelopes. Errors are *EncodeError
+// values pointing at the offending field. Encode only requires these formats
+// under ValidationUPS.
+func ParseSCM(message string) (*SCM, error) {
+	const header = "[)>" + RS
+
+	if !strings.HasPrefix(message, header) {
+		return nil, newEncodeError(ErrInvalidHeader, FieldHeader, 0, "message should start with [)> followed by RS")
+	}
+
+	if !strings.HasSuffix(message, EOT) {
+		return nil, newEncodeError(ErrInvalidHeader, FieldHeader, len(message), "input data should end with EOT marker")
+	}
+
+	offset := len(header)
+	if !strings.HasPrefix(message[offset:], "01"+GS) {
+		return nil, newEncodeError(ErrInvalidHeader, FieldHeader, offset, "message should start with format 01 followed by GS")
+	}
+
+	offset += 3
+	if len(message) < offset+2 || !isDigits(message[offset:offset+2]) {
+		return nil, newEncodeError(ErrInvalidHeader, FieldHeader, offset, "year must be 2 digits")
+	}
+
+	scm := &SCM{
+		Format: "01",
+	}
+
+	scm.Shipment.Year = message[offset : offset+2]
+	offset += 2
+
+	end := strings.Index(message[offset:], RS)
+	if end == -1 {
+		return nil, newEncodeError(ErrInvalidHeader, FieldHeader, len(message)-1, "format 01 envelope should end with RS")
+	}
+
+	end += offset
+	scm.Trailing = message[end+1 : len(message)-1]
+
+	envelopes, err := parseEnvelopes(scm.Trailing, end+1)
+	if err != nil {
+		return nil, err
+	}
+
+	scm.Envelopes = envelopes
+
+	fields := strings.Split(message[offset:end], GS)
+	if len(fields) < 3 {
+		return nil, newEncodeError(ErrInvalidHeader, FieldHeader, offset, "input data should contain postcode, country code and service class separated by Gs")
+	}
+
+	s := &scm.Shipment
+
+	for i, value := range fields {
+		if i >= len(scmFieldNames) {
+			scm.Unknown = fields[i:]
+			break
+		}
+
+		if err := s.setField(scmFieldNames[i], value); err != nil {
+			err.Offset = offset
+			return nil, err
+		}
+
+		offset += len(value) + 1
+	}
+
+	return scm, nil
+}
+
+// setField stores the value of one field of a format 01 envelope.
+func (s *UPSShipment) setField(field Field, value string) *EncodeError {
+	var err error
+
+	switch field {
+	case FieldPostcode:
+		s.Postcode = value
+	case FieldCountry:
+		var codeErr *EncodeError
+		if s.CountryCode, codeErr = threeDigits(value, ErrInvalidCountry, field); codeErr != nil {
+			return codeErr
+		}
+	case FieldServiceClass:
+		var codeErr *EncodeError
+		if s.ServiceClass, codeErr = threeDigits(value, ErrInvalidServiceClass, field); codeErr != nil {
+			return codeErr
+		}
+	case FieldTrackingNumber:
+		s.TrackingNumber = value
+	case FieldSCAC:
+		s.SCAC = value
+	case FieldShipperNumber:
+		s.ShipperNumber = value
+	case FieldPickupDay:
+		if s.PickupDay, err = optionalInt(value); err != nil {
+			return newEncodeError(ErrInvalidField, field, 0, "pickup day must be numeric")
+		}
+	case FieldShipmentID:
+		s.ShipmentID = value
+	case FieldPackage:
+		if value == "" {
+			break
+		}
+
+		n, x, ok := strings.Cut(value, "/")
+		if s.PackageNumber, err = strconv.Atoi(n); !ok || err != nil || !isDigits(n) {
+			return newEncodeError(ErrInvalidField, field, 0, "package must be given as n/x")
+		}
+
+		if s.PackageCount, err = strconv.Atoi(x); err != nil || !isDigits(x) {
+			return newEncodeError(ErrInvalidField, field, 0, "package must be given as n/x")
+		}
+	case FieldWeight:
+		if s.Weight, err = optionalInt(value); err != nil {
+			return newEncodeError(ErrInvalidField, field, 0, "weight must be numeric")
+		}
+	case FieldAddressValidation:
+		switch value {
+		case "Y":
+			s.AddressValidation = true
+		case "N", "":
+		default:
+			return newEncodeError(ErrInvalidField, field, 0, "address validation must be Y or N")
+		}
+	case FieldShipToAddress:
+		s.ShipToAddress = value
+	case FieldShipToCity:
+		s.ShipToCity = value
+	case FieldShipToState:
+		s.ShipToState = value
+	}
+
+	return nil
+}
+
+// splitPrimary splits the postcode, country code and service class off a
+// structured carrier message and returns them with the offset of the rest
+// of the message. Only the format of these fields is checked.
+func splitPrimary(message string) (Primary, int, error) {
+	const postcodeStart = 9
+
+	if !strings.HasSuffix(message, EOT) {
+		return Primary{}, 0, newEncodeError(ErrInvalidHeader, FieldHeader, len(message), "input data should end with EOT marker")
+	}
+
+	if len(message) < postcodeStart {
+		return Primary{}, 0, newEncodeError(ErrInvalidHeader, FieldHeader, len(message), "year must follow the header")
+	}
+
+	groups := strings.SplitN(message[postcodeStart:], GS, 4)
+	if len(groups) < 4 {
+		return Primary{}, 0, newEncodeError(ErrInvalidHeader, FieldHeader, postcodeStart, "input data should contain postcode, country code and service class separated by Gs")
+	}
+
+	countryStart := postcodeStart + len(groups[0]) + 1
+	serviceClassStart := countryStart + len(groups[1]) + 1
+
+	cc, err := threeDigits(groups[1], ErrInvalidCountry, FieldCountry)
+	if err != nil {
+		err.Offset = countryStart
+		return Primary{}, 0, err
+	}
+
+	sc, err := threeDigits(groups[2], ErrInvalidServiceClass, FieldServiceClass)
+	if err != nil {
+		err.Offset = serviceClassStart
+		return Primary{}, 0, err
+	}
+
+	return Primary{Postcode: groups[0], CountryCode: cc, ServiceClass: sc}, serviceClassStart + len(groups[2]) + 1, nil
+}
+
+// threeDigits parses the country code or service class of a primary message.
+func threeDigits(value string, sentinel error, field Field) (int, *EncodeError) {
+	if len(value) != 3 {
+		return 0, newEncodeError(sentinel, field, 0, fmt.Sprintf("invalid %s length", field))
+	}
+
+	if !isDigits(value) {
+		return 0, newEncodeError(sentinel, field, 0, fmt.Sprintf("%s must be numeric", field))
+	}
+
+	n, _ := strconv.Atoi(value)
+
+	return n, nil
+}
+
+func optionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if !isDigits(value) {
+		return 0, strconv.ErrSyntax
+	}
+
+	return strconv.Atoi(value)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+// SCM parses the data of a mode 2 or 3 symbol as a structured carrier message.
+func (r *Result) SCM() (*SCM, error) {
+	if r.Mode != Mode2 && r.Mode != Mode3 {
+		return nil, fmt.Errorf("%v does not carry a structured carrier message", r.Mode)
+	}
+
+	return ParseSCM(r.Data)
+}
+
+// scmOffsets returns where each field of the format 01 envelope starts in a
+// message that ParseSCM accepted.
+func scmOffsets(message string) map[Field]int {
+	offsets := make(map[Field]int, len(scmFieldNames))
+
+	const fieldsStart = 9
+
+	end := fieldsStart + strings.Index(message[fieldsStart:], RS)
+	offset := fieldsStart
+
+	for i, value := range strings.Split(message[fieldsStart:end], GS) {
+		if i >= len(scmFieldNames) {
+			break
+		}
+
+		offsets[scmFieldNames[i]] = offset
+		offset += len(value) + 1
+	}
+
+	return offsets
+}
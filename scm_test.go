@@ -0,0 +1,206 @@
+package maxicode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSCM(t *testing.T) {
+	header := "[)>" + RS + "01" + GS
+
+	testCases := []struct {
+		desc      string
+		message   string
+		expSCM    *SCM
+		expErr    error
+		expField  Field
+		expOffset int
+	}{
+		{
+			desc:    "all fields",
+			message: header + "96841706672" + GS + "840" + GS + "001" + GS + "1Z12345673" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "N" + GS + "19 SOUTH ST" + GS + "SALTLAKE CITY" + GS + "UT" + RS + EOT,
+			expSCM: &SCM{
+				Format: "01",
+				Shipment: UPSShipment{
+					Year:           "96",
+					Postcode:       "841706672",
+					CountryCode:    840,
+					ServiceClass:   1,
+					TrackingNumber: "1Z12345673",
+					SCAC:           "UPSN",
+					ShipperNumber:  "1X2X3X",
+					PickupDay:      187,
+					PackageNumber:  1,
+					PackageCount:   1,
+					Weight:         10,
+					ShipToAddress:  "19 SOUTH ST",
+					ShipToCity:     "SALTLAKE CITY",
+					ShipToState:    "UT",
+				},
+			},
+		},
+		{
+			desc:    "further envelopes",
+			message: header + "96948509751" + GS + "840" + GS + "988" + GS + "1Z28945956" + GS + "UPSN" + GS + "4X7V81" + RS + "07P" + string(rune(28)) + ":3 0+" + RS + EOT,
+			expSCM: &SCM{
+				Format: "01",
+				Shipment: UPSShipment{
+					Year:           "96",
+					Postcode:       "948509751",
+					CountryCode:    840,
+					ServiceClass:   988,
+					TrackingNumber: "1Z28945956",
+					SCAC:           "UPSN",
+					ShipperNumber:  "4X7V81",
+				},
+				Trailing:  "07P" + string(rune(28)) + ":3 0+" + RS,
+				Envelopes: []Envelope{{Format: "07", Data: "P" + string(rune(28)) + ":3 0+"}},
+			},
+		},
+		{
+			desc:    "unknown fields",
+			message: header + "09651147" + GS + "276" + GS + "066" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "Y" + GS + "5 WALDSTRASSE" + GS + "COLOGNE" + GS + "" + GS + "EXTRA" + GS + "MORE" + RS + EOT,
+			expSCM: &SCM{
+				Format: "01",
+				Shipment: UPSShipment{
+					Year:              "09",
+					Postcode:          "651147",
+					CountryCode:       276,
+					ServiceClass:      66,
+					TrackingNumber:    "1Z12345677",
+					SCAC:              "UPSN",
+					ShipperNumber:     "1X2X3X",
+					PickupDay:         187,
+					PackageNumber:     1,
+					PackageCount:      1,
+					Weight:            10,
+					AddressValidation: true,
+					ShipToAddress:     "5 WALDSTRASSE",
+					ShipToCity:        "COLOGNE",
+				},
+				Unknown: []string{"EXTRA", "MORE"},
+			},
+		},
+		{
+			desc:      "missing message header",
+			message:   "01" + GS + "96SW1A1A" + GS + "826" + GS + "001" + RS + EOT,
+			expErr:    ErrInvalidHeader,
+			expField:  FieldHeader,
+			expOffset: 0,
+		},
+		{
+			desc:      "other format",
+			message:   "[)>" + RS + "06" + GS + "96SW1A1A" + GS + "826" + GS + "001" + RS + EOT,
+			expErr:    ErrInvalidHeader,
+			expField:  FieldHeader,
+			expOffset: 4,
+		},
+		{
+			desc:      "year",
+			message:   header + "9X" + GS + "826" + GS + "001" + RS + EOT,
+			expErr:    ErrInvalidHeader,
+			expField:  FieldHeader,
+			expOffset: 7,
+		},
+		{
+			desc:      "missing RS",
+			message:   header + "96SW1A1A" + GS + "826" + GS + "001" + EOT,
+			expErr:    ErrInvalidHeader,
+			expField:  FieldHeader,
+			expOffset: 23,
+		},
+		{
+			desc:      "missing EOT",
+			message:   header + "96SW1A1A" + GS + "826" + GS + "001" + RS,
+			expErr:    ErrInvalidHeader,
+			expField:  FieldHeader,
+			expOffset: 24,
+		},
+		{
+			desc:      "country code",
+			message:   header + "96SW1A1A" + GS + "82X" + GS + "001" + RS + EOT,
+			expErr:    ErrInvalidCountry,
+			expField:  FieldCountry,
+			expOffset: 16,
+		},
+		{
+			desc:      "package",
+			message:   header + "96SW1A1A" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1 OF 2" + RS + EOT,
+			expErr:    ErrInvalidField,
+			expField:  FieldPackage,
+			expOffset: 52,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			scm, err := ParseSCM(tc.message)
+			if tc.expErr != nil {
+				var encodeErr *EncodeError
+				if !errors.Is(err, tc.expErr) || !errors.As(err, &encodeErr) {
+					t.Fatalf("expected %v, got %v", tc.expErr, err)
+				}
+
+				if encodeErr.Field != tc.expField || encodeErr.Offset != tc.expOffset {
+					t.Errorf("expected %s at offset %d, got %s at offset %d", tc.expField, tc.expOffset, encodeErr.Field, encodeErr.Offset)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expSCM, scm); diff != "" {
+				t.Errorf("Parsed message is not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSCMRoundTrip(t *testing.T) {
+	shipment := UPSShipment{
+		Year:              "96",
+		Postcode:          "SW1A1A",
+		CountryCode:       826,
+		ServiceClass:      1,
+		TrackingNumber:    "1Z12345677",
+		SCAC:              "UPSN",
+		ShipperNumber:     "1X2X3X",
+		PickupDay:         32,
+		ShipmentID:        "REF1",
+		PackageNumber:     2,
+		PackageCount:      5,
+		Weight:            3,
+		AddressValidation: true,
+		ShipToAddress:     "10 DOWNING ST",
+		ShipToCity:        "LONDON",
+	}
+
+	message, err := shipment.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grid, err := Encode(3, 0, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Decode(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scm, err := result.SCM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(shipment, scm.Shipment); diff != "" {
+		t.Errorf("Decoded shipment is not equal to expected, diff (-want, +got):\n%s", diff)
+	}
+}
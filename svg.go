@@ -0,0 +1,80 @@
+package maxicode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// SVGOptions change how WriteSVG draws a symbol. The zero value draws black
+// modules on a transparent background.
+type SVGOptions struct {
+	// Foreground and Background are SVG colours, Background is left out
+	// when empty.
+	Foreground string
+	Background string
+
+	// Margin is the quiet zone around the symbol in millimetres.
+	Margin float64
+}
+
+// WriteSVG writes the symbol as an SVG document measured in millimetres, with
+// the bullseye rings and modules as vectors in the same places as Draw puts
+// them.
+func (s *SymbolGrid) WriteSVG(w io.Writer, opts SVGOptions) error {
+	foreground := opts.Foreground
+	if foreground == "" {
+		foreground = "#000"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	width, height := decimal(symbolWidth+2*opts.Margin), decimal(symbolHeight+2*opts.Margin)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%smm" height="%smm" viewBox="0 0 %s %s">`+"\n", width, height, width, height)
+
+	if opts.Background != "" {
+		fmt.Fprintf(bw, `<rect width="%s" height="%s" fill="%s"/>`+"\n", width, height, opts.Background)
+	}
+
+	fmt.Fprintf(bw, `<g transform="translate(%s %s)">`+"\n", decimal(opts.Margin), decimal(opts.Margin))
+
+	fmt.Fprintf(bw, `<g fill="none" stroke="%s" stroke-width="%s">`+"\n", foreground, decimal(bullseyeLineWidth))
+	for _, r := range bullseyeRadii {
+		fmt.Fprintf(bw, `<circle cx="%s" cy="%s" r="%s"/>`+"\n", decimal(bullseyeX), decimal(bullseyeY), decimal(r))
+	}
+	fmt.Fprintln(bw, `</g>`)
+
+	fmt.Fprintf(bw, `<path fill="%s" d="`, foreground)
+	for row := range 33 {
+		for column := range 30 {
+			if !s.GetModule(row, column) {
+				continue
+			}
+
+			for i, p := range hexagon(row, column) {
+				command := "L"
+				if i == 0 {
+					command = "M"
+				}
+
+				fmt.Fprintf(bw, "%s%s %s", command, decimal(p.x), decimal(p.y))
+			}
+
+			bw.WriteString("Z")
+		}
+	}
+	fmt.Fprintln(bw, `"/>`)
+
+	fmt.Fprintln(bw, `</g>`)
+	fmt.Fprintln(bw, `</svg>`)
+
+	return bw.Flush()
+}
+
+// decimal formats v with at most 4 decimals.
+func decimal(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,105 @@
+package maxicode
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// testGrid returns the mode 3 symbol used by the renderer tests.
+func testGrid(t *testing.T) *SymbolGrid {
+	t.Helper()
+
+	grid, err := Encode(3, 0, "[)>"+RS+"01"+GS+"09651147"+GS+"276"+GS+"066"+GS+"1Z12345677"+GS+"UPSN"+GS+"1X2X3X"+GS+"187"+GS+""+GS+"1/1"+GS+"10"+GS+"N"+GS+"5 WALDSTRASSE"+GS+"COLOGNE"+GS+""+RS+""+EOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return grid
+}
+
+// countModules returns the number of dark modules, which every renderer
+// draws as one hexagon each.
+func countModules(grid *SymbolGrid) int {
+	modules := 0
+	for _, module := range grid {
+		if module {
+			modules++
+		}
+	}
+
+	return modules
+}
+
+func TestHexagonMatchesDraw(t *testing.T) {
+	const dpmm = 10
+
+	grid := testGrid(t)
+	img := grid.Draw(dpmm).Image()
+
+	for row := range 33 {
+		for column := range 30 {
+			// Positions without a module are covered by the bullseye.
+			if maxiGrid[row*30+column] == 0 {
+				continue
+			}
+
+			var center point
+			for _, p := range hexagon(row, column) {
+				center.x += p.x / 6
+				center.y += p.y / 6
+			}
+
+			_, _, _, a := img.At(int(center.x*dpmm), int(center.y*dpmm)).RGBA()
+			if dark := a > 0x8000; dark != grid.GetModule(row, column) {
+				t.Fatalf("module %d, %d is %v in the image, expected %v", row, column, dark, grid.GetModule(row, column))
+			}
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	grid := testGrid(t)
+
+	modules := countModules(grid)
+
+	var buf bytes.Buffer
+	if err := grid.WriteSVG(&buf, SVGOptions{Background: "#fff", Margin: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var svg struct {
+		Width  string `xml:"width,attr"`
+		Height string `xml:"height,attr"`
+		Rect   struct {
+			Fill string `xml:"fill,attr"`
+		} `xml:"rect"`
+		Group struct {
+			Rings struct {
+				Circles []struct {
+					R string `xml:"r,attr"`
+				} `xml:"circle"`
+			} `xml:"g"`
+			Path struct {
+				D string `xml:"d,attr"`
+			} `xml:"path"`
+		} `xml:"g"`
+	}
+
+	if err := xml.Unmarshal(buf.Bytes(), &svg); err != nil {
+		t.Fatal(err)
+	}
+
+	if svg.Width != "30mm" || svg.Height != "28.8mm" || svg.Rect.Fill != "#fff" {
+		t.Errorf("unexpected document %s by %s with background %q", svg.Width, svg.Height, svg.Rect.Fill)
+	}
+
+	if n := len(svg.Group.Rings.Circles); n != 3 {
+		t.Errorf("expected 3 bullseye rings, got %d", n)
+	}
+
+	if n := strings.Count(svg.Group.Path.D, "Z"); n != modules {
+		t.Errorf("expected %d hexagons, got %d", modules, n)
+	}
+}
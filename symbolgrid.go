@@ -13,29 +13,36 @@ func (s *SymbolGrid) GetModule(row, column int) bool {
 }
 
 func (s *SymbolGrid) Draw(dpmm float64) *gg.Context {
-	centerX := 13.64 * dpmm
-	centerY := 13.43 * dpmm
+	dc := gg.NewContext(int(symbolWidth*dpmm), int(symbolHeight*dpmm))
+	s.drawPaths(dc, dpmm)
 
-	innerRadius := 0.85 * dpmm
-	centerRadius := 2.20 * dpmm
-	outerRadius := 3.54 * dpmm
+	return dc
+}
 
-	dc := gg.NewContext(int(28*dpmm), int(26.8*dpmm))
+// DrawIntoContext draws the symbol into dc like Draw, rotated clockwise by
+// rotation degrees, with the top left corner of the bounding box of the
+// rotated symbol at x, y. The transformation, colour and line width of dc
+// are restored afterwards.
+func (s *SymbolGrid) DrawIntoContext(dc *gg.Context, x, y, dpmm, rotation float64) {
+	t := placement(dpmm, rotation, x, y)
 
-	// Central bullseye patterns.
-	dc.SetLineWidth(0.67 * dpmm)
+	dc.Push()
+	defer dc.Pop()
 
-	dc.DrawCircle(centerX, centerY, outerRadius)
-	dc.SetRGB(0, 0, 0)
-	dc.Stroke()
+	dc.Translate(t.c, t.f)
+	dc.Rotate(gg.Radians(rotation))
+	s.drawPaths(dc, dpmm)
+}
 
-	dc.DrawCircle(centerX, centerY, centerRadius)
-	dc.SetRGB(0, 0, 0)
-	dc.Stroke()
+func (s *SymbolGrid) drawPaths(dc *gg.Context, dpmm float64) {
+	// Central bullseye patterns.
+	dc.SetLineWidth(bullseyeLineWidth * dpmm)
 
-	dc.DrawCircle(centerX, centerY, innerRadius)
-	dc.SetRGB(0, 0, 0)
-	dc.Stroke()
+	for _, r := range bullseyeRadii {
+		dc.DrawCircle(bullseyeX*dpmm, bullseyeY*dpmm, r*dpmm)
+		dc.SetRGB(0, 0, 0)
+		dc.Stroke()
+	}
 
 	// Hexagons
 	for row := range 33 {
@@ -44,30 +51,15 @@ func (s *SymbolGrid) Draw(dpmm float64) *gg.Context {
 				continue
 			}
 
-			rowOffset := 0.88
-			if (row & 1) == 1 {
-				rowOffset = 1.32
-			}
-
-			col := float64(column)
-			row := float64(row)
-
-			hexRectX := (float64(col*0.88) + rowOffset) * dpmm
-			hexRectY := (float64(row*0.76) + 0.76) * dpmm
-			hexRectW := 0.76 * dpmm
-			hexRectH := 0.88 * dpmm
+			corners := hexagon(row, column)
 
-			dc.MoveTo(hexRectX+float64(hexRectW*0.5), hexRectY)
-			dc.LineTo(hexRectX+hexRectW, hexRectY+float64(hexRectH*0.25))
-			dc.LineTo(hexRectX+hexRectW, hexRectY+float64(hexRectH*0.75))
-			dc.LineTo(hexRectX+float64(hexRectW*0.5), hexRectY+hexRectH)
-			dc.LineTo(hexRectX, hexRectY+float64(hexRectH*0.75))
-			dc.LineTo(hexRectX, hexRectY+float64(hexRectH*0.25))
+			dc.MoveTo(corners[0].x*dpmm, corners[0].y*dpmm)
+			for _, p := range corners[1:] {
+				dc.LineTo(p.x*dpmm, p.y*dpmm)
+			}
 			dc.Fill()
 		}
 	}
-
-	return dc
 }
 
 func (s *SymbolGrid) SaveToPNG(multiplier float64, path string) error {
@@ -0,0 +1,140 @@
+package maxicode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UPSShipment holds the fields of a UPS structured carrier message, the data
+// that modes 2 and 3 expect.
+type UPSShipment struct {
+	// Year is the two digit version of the message format, "96" when empty.
+	Year string
+
+	Postcode    string
+	CountryCode int // ISO 3166 numeric
+	// Country is an ISO 3166 alpha-2, alpha-3 or numeric code, used when
+	// CountryCode is 0.
+	Country      string
+	ServiceClass int
+
+	TrackingNumber string
+	SCAC           string // Standard Carrier Alpha Code, "UPSN" for UPS
+	ShipperNumber  string
+	PickupDay      int // Julian day of the year
+	ShipmentID     string
+
+	// PackageNumber of PackageCount packages in the shipment.
+	PackageNumber int
+	PackageCount  int
+
+	Weight            int // in whole pounds
+	AddressValidation bool
+
+	ShipToAddress string
+	ShipToCity    string
+	ShipToState   string
+}
+
+// Message returns the structured carrier message for the shipment, ready to
+// be given to Encode in mode 2 or 3, with the envelopes following the format
+// 01 one. Fields may not contain the RS, GS or EOT separators.
+func (s *UPSShipment) Message(envelopes ...Envelope) (string, error) {
+	year := s.Year
+	if year == "" {
+		year = "96"
+	}
+
+	if len(year) != 2 || !isDigits(year) {
+		return "", newEncodeError(ErrInvalidField, FieldHeader, -1, fmt.Sprintf("year must have 2 digits, got %q", year))
+	}
+
+	country, err := s.country()
+	if err != nil {
+		return "", err
+	}
+
+	packages := ""
+	if s.PackageNumber > 0 || s.PackageCount > 0 {
+		packages = fmt.Sprintf("%d/%d", s.PackageNumber, s.PackageCount)
+	}
+
+	fields := []struct {
+		name  Field
+		value string
+	}{
+		{FieldPostcode, s.Postcode},
+		{FieldCountry, fmt.Sprintf("%03d", country.Numeric)},
+		{FieldServiceClass, fmt.Sprintf("%03d", s.ServiceClass)},
+		{FieldTrackingNumber, s.TrackingNumber},
+		{FieldSCAC, s.SCAC},
+		{FieldShipperNumber, s.ShipperNumber},
+		{FieldPickupDay, optionalNumber(s.PickupDay, 3)},
+		{FieldShipmentID, s.ShipmentID},
+		{FieldPackage, packages},
+		{FieldWeight, optionalNumber(s.Weight, 0)},
+		{FieldAddressValidation, yesNo(s.AddressValidation)},
+		{FieldShipToAddress, s.ShipToAddress},
+		{FieldShipToCity, s.ShipToCity},
+		{FieldShipToState, s.ShipToState},
+	}
+
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		if strings.ContainsAny(field.value, RS+GS+EOT) {
+			return "", newEncodeError(ErrInvalidField, field.name, -1, fmt.Sprintf("%s contains a separator character", field.name))
+		}
+
+		values[i] = field.value
+	}
+
+	var trailing strings.Builder
+	for _, envelope := range envelopes {
+		if err := envelope.validate(); err != nil {
+			return "", err
+		}
+
+		trailing.WriteString(envelope.String())
+	}
+
+	return "[)>" + RS + "01" + GS + year + strings.Join(values, GS) + RS + trailing.String() + EOT, nil
+}
+
+// Mode returns the mode for the shipment, picked from the postcode format of
+// its country. UPS only uses mode 2 for the United States.
+func (s *UPSShipment) Mode() (Mode, error) {
+	country, err := s.country()
+	if err != nil {
+		return 0, err
+	}
+
+	if country.Mode() == Mode2 && country.Numeric == 840 {
+		return Mode2, nil
+	}
+
+	return Mode3, nil
+}
+
+func (s *UPSShipment) country() (Country, error) {
+	if s.CountryCode != 0 {
+		return LookupCountry(fmt.Sprintf("%03d", s.CountryCode))
+	}
+
+	return LookupCountry(s.Country)
+}
+
+func optionalNumber(n, width int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%0*d", width, n)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Y"
+	}
+
+	return "N"
+}
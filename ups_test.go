@@ -0,0 +1,140 @@
+package maxicode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUPSShipmentMessage(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		shipment   UPSShipment
+		expMessage string
+		expErr     string
+		expErrIs   error
+	}{
+		{
+			desc: "mode 2",
+			shipment: UPSShipment{
+				Postcode:       "841706672",
+				CountryCode:    840,
+				ServiceClass:   1,
+				TrackingNumber: "1Z12345673",
+				SCAC:           "UPSN",
+				ShipperNumber:  "1X2X3X",
+				PickupDay:      187,
+				PackageNumber:  1,
+				PackageCount:   1,
+				Weight:         10,
+				ShipToAddress:  "19 SOUTH ST",
+				ShipToCity:     "SALTLAKE CITY",
+				ShipToState:    "UT",
+			},
+			expMessage: "[)>" + RS + "01" + GS + "96841706672" + GS + "840" + GS + "001" + GS + "1Z12345673" + GS + "UPSN" + GS + "1X2X3X" + GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "N" + GS + "19 SOUTH ST" + GS + "SALTLAKE CITY" + GS + "UT" + RS + EOT,
+		},
+		{
+			desc: "mode 3 with address validation",
+			shipment: UPSShipment{
+				Year:              "09",
+				Postcode:          "651147",
+				CountryCode:       276,
+				ServiceClass:      66,
+				TrackingNumber:    "1Z12345677",
+				SCAC:              "UPSN",
+				ShipperNumber:     "1X2X3X",
+				PickupDay:         7,
+				ShipmentID:        "SHIP01",
+				PackageNumber:     2,
+				PackageCount:      3,
+				AddressValidation: true,
+				ShipToAddress:     "5 WALDSTRASSE",
+				ShipToCity:        "COLOGNE",
+			},
+			expMessage: "[)>" + RS + "01" + GS + "09651147" + GS + "276" + GS + "066" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "007" + GS + "SHIP01" + GS + "2/3" + GS + "" + GS + "Y" + GS + "5 WALDSTRASSE" + GS + "COLOGNE" + GS + "" + RS + EOT,
+		},
+		{
+			desc: "alpha-3 country",
+			shipment: UPSShipment{
+				Postcode:       "SW1A1A",
+				Country:        "GBR",
+				ServiceClass:   1,
+				TrackingNumber: "1Z12345677",
+				SCAC:           "UPSN",
+				ShipperNumber:  "1X2X3X",
+			},
+			expMessage: "[)>" + RS + "01" + GS + "96SW1A1A" + GS + "826" + GS + "001" + GS + "1Z12345677" + GS + "UPSN" + GS + "1X2X3X" + GS + "" + GS + "" + GS + "" + GS + "" + GS + "N" + GS + "" + GS + "" + GS + "" + RS + EOT,
+		},
+		{
+			desc: "unknown country",
+			shipment: UPSShipment{
+				Postcode: "SW1A1A",
+				Country:  "XX",
+			},
+			expErr:   `"XX" is not an ISO 3166 country code`,
+			expErrIs: ErrInvalidCountry,
+		},
+		{
+			desc: "separator in a field",
+			shipment: UPSShipment{
+				Postcode:      "SW1A1A",
+				CountryCode:   826,
+				ServiceClass:  1,
+				ShipToAddress: "10 DOWNING ST" + GS + "LONDON",
+			},
+			expErr:   "ship to address contains a separator character",
+			expErrIs: ErrInvalidField,
+		},
+		{
+			desc: "year",
+			shipment: UPSShipment{
+				Year: "1996",
+			},
+			expErr:   `year must have 2 digits, got "1996"`,
+			expErrIs: ErrInvalidField,
+		},
+		{
+			desc: "year with a letter",
+			shipment: UPSShipment{
+				Year: "9A",
+			},
+			expErr:   `year must have 2 digits, got "9A"`,
+			expErrIs: ErrInvalidField,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			message, err := tc.shipment.Message()
+			if tc.expErr != "" {
+				if err == nil || err.Error() != tc.expErr {
+					t.Fatalf("expected error %q, got %v", tc.expErr, err)
+				}
+
+				var encodeErr *EncodeError
+				if !errors.Is(err, tc.expErrIs) || !errors.As(err, &encodeErr) {
+					t.Errorf("expected an *EncodeError wrapping %v, got %T", tc.expErrIs, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if message != tc.expMessage {
+				t.Errorf("expected %q, got %q", tc.expMessage, message)
+			}
+
+			// The message must be accepted by the encoder as it is.
+			mode := 3
+			if tc.shipment.CountryCode == 840 {
+				mode = 2
+			}
+
+			if _, err := Encode(mode, 0, message); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
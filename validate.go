@@ -0,0 +1,200 @@
+package maxicode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validation is a profile of the checks applied to the messages of modes 2
+// and 3.
+type Validation int
+
+const (
+	// ValidationNone keeps the checks Encode has always made: the symbology
+	// rules, and mode 2 only for the United States. Country codes missing
+	// from the ISO 3166 table are encoded as they are. Postcode characters
+	// mode 3 cannot carry are an error unless WithWarnings is given.
+	ValidationNone Validation = iota
+	// ValidationUPS adds to ValidationNone that country codes must be in the
+	// ISO 3166 table, the formats of every field of the message as ParseSCM
+	// reads them, and the UPS rules for their values, reporting every rule
+	// broken in a *ValidationError.
+	ValidationUPS
+	// ValidationISO applies only the ISO/IEC 16023 rules to the postcode,
+	// country code and service class, so mode 2 carries numeric postcodes of
+	// any country, and requires country codes from the ISO 3166 table. The
+	// other fields are encoded as they are. Spaces and hyphens are dropped
+	// from mode 2 postcodes and reported to the WithWarnings handler, other
+	// postcode characters mode 3 cannot carry are an error.
+	ValidationISO
+	// ValidationLenient applies the rules of ValidationISO but encodes
+	// country codes missing from the ISO 3166 table as they are, and replaces
+	// postcode characters mode 3 cannot carry with spaces, reporting them to
+	// the WithWarnings handler.
+	ValidationLenient
+)
+
+// WithValidation selects the validation profile, ValidationNone by default.
+func WithValidation(validation Validation) Option {
+	return func(e *Encoder) {
+		e.validation = validation
+	}
+}
+
+// usOnlyMode2 reports whether the profile applies the UPS rule that mode 2 is
+// only used for the United States.
+func (v Validation) usOnlyMode2() bool {
+	return v == ValidationNone || v == ValidationUPS
+}
+
+// requiresUPSFields reports whether messages must be long enough to hold the
+// tracking number and SCAC that follow the primary fields.
+func (v Validation) requiresUPSFields() bool {
+	return v == ValidationNone || v == ValidationUPS
+}
+
+// dropsPostcodeSeparators reports whether spaces and hyphens are dropped from
+// mode 2 postcodes rather than rejected.
+func (v Validation) dropsPostcodeSeparators() bool {
+	return v == ValidationISO || v == ValidationLenient
+}
+
+// knownCountriesOnly reports whether country codes must be in the ISO 3166
+// table.
+func (v Validation) knownCountriesOnly() bool {
+	return v == ValidationUPS || v == ValidationISO
+}
+
+// replacesPostcodeChars reports whether postcode characters mode 3 cannot
+// carry are replaced rather than rejected.
+func (v Validation) replacesPostcodeChars(warn func(Warning)) bool {
+	return v == ValidationLenient || (v == ValidationNone && warn != nil)
+}
+
+// upsSCACs are the Standard Carrier Alpha Codes UPS labels are printed with.
+var upsSCACs = map[string]bool{
+	"UPSN": true, // UPS
+	"UPSC": true, // UPS Supply Chain Solutions
+	"UPGF": true, // UPS Ground Freight
+}
+
+// upsServiceClasses are the published UPS service levels.
+var upsServiceClasses = map[int]string{
+	1:  "Next Day Air",
+	2:  "2nd Day Air",
+	3:  "Ground",
+	7:  "Worldwide Express",
+	8:  "Worldwide Expedited",
+	11: "Standard",
+	12: "3 Day Select",
+	13: "Next Day Air Saver",
+	14: "Next Day Air Early",
+	54: "Worldwide Express Plus",
+	59: "2nd Day Air A.M.",
+	65: "Worldwide Saver",
+}
+
+// Validate checks the shipment against the UPS rules and returns a
+// *ValidationError listing every problem found, or nil.
+func (s *UPSShipment) Validate() error {
+	if problems := s.upsProblems(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func (s *UPSShipment) upsProblems() []*EncodeError {
+	var problems []*EncodeError
+
+	report := func(err error, field Field, format string, args ...any) {
+		problems = append(problems, newEncodeError(err, field, -1, fmt.Sprintf(format, args...)))
+	}
+
+	if _, ok := upsServiceClasses[s.ServiceClass]; !ok {
+		report(ErrInvalidServiceClass, FieldServiceClass, "service class %03d is not a UPS service", s.ServiceClass)
+	}
+
+	if t := s.TrackingNumber; !strings.HasPrefix(t, "1Z") || !isUpperAlnum(t[2:]) || (len(t) != 10 && len(t) != 18) {
+		report(ErrInvalidField, FieldTrackingNumber, "tracking number %q must be 1Z followed by 8 or 16 letters and digits", t)
+	} else if len(t) == 18 {
+		if check := upsCheckDigit(t[2:17]); check != t[17] {
+			report(ErrInvalidCheckDigit, FieldTrackingNumber, "tracking number %q should end with check digit %c", t, check)
+		}
+
+		if len(s.ShipperNumber) == 6 && t[2:8] != s.ShipperNumber {
+			report(ErrInvalidField, FieldTrackingNumber, "tracking number %q does not belong to shipper number %s", t, s.ShipperNumber)
+		}
+	}
+
+	if !upsSCACs[s.SCAC] {
+		report(ErrInvalidField, FieldSCAC, "SCAC %q is not a UPS carrier code", s.SCAC)
+	}
+
+	if len(s.ShipperNumber) != 6 || !isUpperAlnum(s.ShipperNumber) {
+		report(ErrInvalidField, FieldShipperNumber, "shipper number %q must be 6 letters and digits", s.ShipperNumber)
+	}
+
+	if s.PickupDay < 0 || s.PickupDay > 366 {
+		report(ErrInvalidField, FieldPickupDay, "pickup day %d is not a day of the year", s.PickupDay)
+	}
+
+	if s.PackageNumber < 0 || s.PackageCount < 0 || s.PackageNumber > s.PackageCount {
+		report(ErrInvalidField, FieldPackage, "package %d/%d is out of range", s.PackageNumber, s.PackageCount)
+	}
+
+	return problems
+}
+
+// upsCheckDigit returns the check digit of the 15 characters following the
+// 1Z of a tracking number. Letters count as their position in the alphabet
+// plus one, modulo 10, and the characters at even positions counting from
+// one are doubled.
+func upsCheckDigit(s string) byte {
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		v := int(s[i] - '0')
+		if s[i] >= 'A' {
+			v = int(s[i]-'A'+2) % 10
+		}
+
+		if i%2 == 1 {
+			v *= 2
+		}
+
+		sum += v
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isUpperAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'A' || s[i] > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateUPS parses a structured carrier message and checks it against the
+// UPS rules, pointing every problem at its field in the message.
+func validateUPS(message string) error {
+	scm, err := ParseSCM(message)
+	if err != nil {
+		return err
+	}
+
+	problems := scm.Shipment.upsProblems()
+	if len(problems) == 0 {
+		return nil
+	}
+
+	offsets := scmOffsets(message)
+	for _, problem := range problems {
+		problem.Offset = offsets[problem.Field]
+	}
+
+	return &ValidationError{Problems: problems}
+}
@@ -0,0 +1,289 @@
+package maxicode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type problem struct {
+	Err    error
+	Field  Field
+	Offset int
+}
+
+func validationProblems(t *testing.T, err error) []problem {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+
+	problems := make([]problem, len(validationErr.Problems))
+	for i, p := range validationErr.Problems {
+		problems[i] = problem{p.Err, p.Field, p.Offset}
+	}
+
+	return problems
+}
+
+func TestUPSShipmentValidate(t *testing.T) {
+	valid := UPSShipment{
+		Postcode:       "841706672",
+		CountryCode:    840,
+		ServiceClass:   1,
+		TrackingNumber: "1Z12345E6605272234",
+		SCAC:           "UPSN",
+		ShipperNumber:  "12345E",
+		PickupDay:      187,
+		PackageNumber:  1,
+		PackageCount:   2,
+	}
+
+	testCases := []struct {
+		desc   string
+		modify func(s *UPSShipment)
+		exp    []problem
+	}{
+		{
+			desc:   "valid",
+			modify: func(s *UPSShipment) {},
+		},
+		{
+			desc: "short tracking number",
+			modify: func(s *UPSShipment) {
+				s.TrackingNumber = "1Z14647438"
+				s.PickupDay = 0
+			},
+		},
+		{
+			desc: "check digit",
+			modify: func(s *UPSShipment) {
+				s.TrackingNumber = "1Z12345E6605272235"
+			},
+			exp: []problem{
+				{ErrInvalidCheckDigit, FieldTrackingNumber, -1},
+			},
+		},
+		{
+			desc: "other shipper",
+			modify: func(s *UPSShipment) {
+				s.ShipperNumber = "1X2X3X"
+			},
+			exp: []problem{
+				{ErrInvalidField, FieldTrackingNumber, -1},
+			},
+		},
+		{
+			desc: "check digit and other shipper",
+			modify: func(s *UPSShipment) {
+				s.TrackingNumber = "1Z12345E6605272235"
+				s.ShipperNumber = "1X2X3X"
+			},
+			exp: []problem{
+				{ErrInvalidCheckDigit, FieldTrackingNumber, -1},
+				{ErrInvalidField, FieldTrackingNumber, -1},
+			},
+		},
+		{
+			desc: "every problem",
+			modify: func(s *UPSShipment) {
+				s.ServiceClass = 988
+				s.TrackingNumber = "1Z1234"
+				s.SCAC = "ACME"
+				s.ShipperNumber = "1x2x3x"
+				s.PickupDay = 367
+				s.PackageNumber = 3
+			},
+			exp: []problem{
+				{ErrInvalidServiceClass, FieldServiceClass, -1},
+				{ErrInvalidField, FieldTrackingNumber, -1},
+				{ErrInvalidField, FieldSCAC, -1},
+				{ErrInvalidField, FieldShipperNumber, -1},
+				{ErrInvalidField, FieldPickupDay, -1},
+				{ErrInvalidField, FieldPackage, -1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			shipment := valid
+			tc.modify(&shipment)
+
+			problems := validationProblems(t, shipment.Validate())
+			if diff := cmp.Diff(tc.exp, problems, cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+				t.Errorf("Problems are not equal to expected, diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUPSCheckDigit(t *testing.T) {
+	// Published UPS tracking numbers.
+	for _, trackingNumber := range []string{
+		"1Z999AA10123456784",
+		"1Z12345E6605272234",
+		"1Z648616E192760718",
+		"1ZWX0692YP40636269",
+	} {
+		if got := upsCheckDigit(trackingNumber[2:17]); got != trackingNumber[17] {
+			t.Errorf("%s: expected check digit %c, got %c", trackingNumber, trackingNumber[17], got)
+		}
+	}
+}
+
+func TestEncoderValidation(t *testing.T) {
+	encoder, err := NewEncoder(Mode2, WithValidation(ValidationUPS))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := "[)>" + RS + "01" + GS + "96841706672" + GS + "840" + GS + "001" + GS + "1Z12345E6605272234" + GS + "UPSN" + GS + "12345E" + GS + "187" + RS + EOT
+	if _, err := encoder.Encode(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := "[)>" + RS + "01" + GS + "96841706672" + GS + "840" + GS + "988" + GS + "1Z28945956" + GS + "UPS" + GS + "4X7V81" + RS + EOT
+
+	_, err = encoder.Encode(invalid)
+	if !errors.Is(err, ErrInvalidServiceClass) || !errors.Is(err, ErrInvalidField) {
+		t.Fatalf("expected service class and field errors, got %v", err)
+	}
+
+	exp := []problem{
+		{ErrInvalidServiceClass, FieldServiceClass, 23},
+		{ErrInvalidField, FieldSCAC, 38},
+	}
+
+	if diff := cmp.Diff(exp, validationProblems(t, err), cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+		t.Errorf("Problems are not equal to expected, diff (-want, +got):\n%s", diff)
+	}
+
+	if _, err := Encode(2, 0, invalid); err != nil {
+		t.Errorf("expected no validation without the UPS profile, got %v", err)
+	}
+}
+
+func TestValidationProfiles(t *testing.T) {
+	rest := GS + "001" + GS + "1Z12345E6605272234" + GS + "UPSN" + GS + "12345E" + GS + "187" + RS + EOT
+	germanMode2 := "[)>" + RS + "01" + GS + "96" + "65114" + GS + "276" + rest
+	invalidPostcode := "[)>" + RS + "01" + GS + "96" + "SW1A!A" + GS + "826" + rest
+	zipPlus4 := "[)>" + RS + "01" + GS + "96" + "90210-1234" + GS + "840" + rest
+	primaryOnly := "[)>" + RS + "01" + GS + "96" + "12345" + GS + "276" + GS + "001" + GS + "ROUTE 7" + RS + EOT
+	unknownCountry := "[)>" + RS + "01" + GS + "96" + "SW1A1A" + GS + "999" + rest
+
+	// Fields after the service class only have to follow the UPS formats
+	// under ValidationUPS, the other profiles check the primary fields only.
+	fields := func(fields string) string {
+		return "[)>" + RS + "01" + GS + "96" + "SW1A1A" + GS + "826" + GS + "001" + GS + "1Z12345E6605272234" + GS + "UPSN" + GS + "12345E" + fields + EOT
+	}
+
+	weight := fields(GS + "187" + GS + "" + GS + "1/1" + GS + "10.5" + RS)
+	pickupDay := fields(GS + "ABC" + RS)
+	packages := fields(GS + "187" + GS + "" + GS + "1" + RS)
+	addressValidation := fields(GS + "187" + GS + "" + GS + "1/1" + GS + "10" + GS + "X" + RS)
+	noRS := fields(GS + "187")
+
+	// So does the data after the format 01 envelope.
+	freeText := fields(RS + "FRAGILE")
+	unknownAI := fields(RS + "05" + GS + "5012" + RS)
+
+	testCases := []struct {
+		desc        string
+		mode        Mode
+		validation  Validation
+		inputData   string
+		expErr      error
+		expWarnings int
+	}{
+		{desc: "mode 2 outside the US", mode: Mode2, validation: ValidationNone, inputData: germanMode2, expErr: ErrInvalidCountry},
+		{desc: "mode 2 outside the US for UPS", mode: Mode2, validation: ValidationUPS, inputData: germanMode2, expErr: ErrInvalidCountry},
+		{desc: "mode 2 outside the US for ISO", mode: Mode2, validation: ValidationISO, inputData: germanMode2},
+		{desc: "mode 2 outside the US when lenient", mode: Mode2, validation: ValidationLenient, inputData: germanMode2},
+		{desc: "postcode character", mode: Mode3, validation: ValidationNone, inputData: invalidPostcode, expErr: ErrInvalidPostcode},
+		{desc: "postcode character for UPS", mode: Mode3, validation: ValidationUPS, inputData: invalidPostcode, expErr: ErrInvalidPostcode},
+		{desc: "postcode character for ISO", mode: Mode3, validation: ValidationISO, inputData: invalidPostcode, expErr: ErrInvalidPostcode},
+		{desc: "postcode character when lenient", mode: Mode3, validation: ValidationLenient, inputData: invalidPostcode, expWarnings: 1},
+		{desc: "postcode separator in mode 2", mode: Mode2, validation: ValidationNone, inputData: zipPlus4, expErr: ErrInvalidPostcode},
+		{desc: "postcode separator in mode 2 for UPS", mode: Mode2, validation: ValidationUPS, inputData: zipPlus4, expErr: ErrInvalidPostcode},
+		{desc: "postcode separator in mode 2 for ISO", mode: Mode2, validation: ValidationISO, inputData: zipPlus4, expWarnings: 1},
+		{desc: "postcode separator in mode 2 when lenient", mode: Mode2, validation: ValidationLenient, inputData: zipPlus4, expWarnings: 1},
+		{desc: "primary fields only", mode: Mode3, validation: ValidationNone, inputData: primaryOnly, expErr: ErrInvalidHeader},
+		{desc: "primary fields only for UPS", mode: Mode3, validation: ValidationUPS, inputData: primaryOnly, expErr: ErrInvalidField},
+		{desc: "primary fields only for ISO", mode: Mode3, validation: ValidationISO, inputData: primaryOnly},
+		{desc: "primary fields only when lenient", mode: Mode3, validation: ValidationLenient, inputData: primaryOnly},
+		{desc: "primary fields only in mode 2 for ISO", mode: Mode2, validation: ValidationISO, inputData: primaryOnly},
+		{desc: "primary fields only in mode 2 when lenient", mode: Mode2, validation: ValidationLenient, inputData: primaryOnly},
+		{desc: "unknown country", mode: Mode3, validation: ValidationNone, inputData: unknownCountry},
+		{desc: "unknown country for UPS", mode: Mode3, validation: ValidationUPS, inputData: unknownCountry, expErr: ErrInvalidCountry},
+		{desc: "unknown country for ISO", mode: Mode3, validation: ValidationISO, inputData: unknownCountry, expErr: ErrInvalidCountry},
+		{desc: "unknown country when lenient", mode: Mode3, validation: ValidationLenient, inputData: unknownCountry},
+		{desc: "decimal weight", mode: Mode3, validation: ValidationNone, inputData: weight},
+		{desc: "decimal weight for UPS", mode: Mode3, validation: ValidationUPS, inputData: weight, expErr: ErrInvalidField},
+		{desc: "letters in pickup day", mode: Mode3, validation: ValidationNone, inputData: pickupDay},
+		{desc: "letters in pickup day for UPS", mode: Mode3, validation: ValidationUPS, inputData: pickupDay, expErr: ErrInvalidField},
+		{desc: "package without count", mode: Mode3, validation: ValidationNone, inputData: packages},
+		{desc: "package without count for UPS", mode: Mode3, validation: ValidationUPS, inputData: packages, expErr: ErrInvalidField},
+		{desc: "address validation", mode: Mode3, validation: ValidationNone, inputData: addressValidation},
+		{desc: "address validation for UPS", mode: Mode3, validation: ValidationUPS, inputData: addressValidation, expErr: ErrInvalidField},
+		{desc: "no RS", mode: Mode3, validation: ValidationNone, inputData: noRS},
+		{desc: "no RS for UPS", mode: Mode3, validation: ValidationUPS, inputData: noRS, expErr: ErrInvalidHeader},
+		{desc: "decimal weight for ISO", mode: Mode3, validation: ValidationISO, inputData: weight},
+		{desc: "letters in pickup day for ISO", mode: Mode3, validation: ValidationISO, inputData: pickupDay},
+		{desc: "package without count for ISO", mode: Mode3, validation: ValidationISO, inputData: packages},
+		{desc: "address validation for ISO", mode: Mode3, validation: ValidationISO, inputData: addressValidation},
+		{desc: "no RS for ISO", mode: Mode3, validation: ValidationISO, inputData: noRS},
+		{desc: "decimal weight when lenient", mode: Mode3, validation: ValidationLenient, inputData: weight},
+		{desc: "letters in pickup day when lenient", mode: Mode3, validation: ValidationLenient, inputData: pickupDay},
+		{desc: "package without count when lenient", mode: Mode3, validation: ValidationLenient, inputData: packages},
+		{desc: "address validation when lenient", mode: Mode3, validation: ValidationLenient, inputData: addressValidation},
+		{desc: "no RS when lenient", mode: Mode3, validation: ValidationLenient, inputData: noRS},
+		{desc: "free text after the envelope", mode: Mode3, validation: ValidationNone, inputData: freeText},
+		{desc: "free text after the envelope for UPS", mode: Mode3, validation: ValidationUPS, inputData: freeText, expErr: ErrInvalidHeader},
+		{desc: "free text after the envelope for ISO", mode: Mode3, validation: ValidationISO, inputData: freeText},
+		{desc: "free text after the envelope when lenient", mode: Mode3, validation: ValidationLenient, inputData: freeText},
+		{desc: "unknown application identifier", mode: Mode3, validation: ValidationNone, inputData: unknownAI},
+		{desc: "unknown application identifier for UPS", mode: Mode3, validation: ValidationUPS, inputData: unknownAI, expErr: ErrInvalidField},
+		{desc: "unknown application identifier for ISO", mode: Mode3, validation: ValidationISO, inputData: unknownAI},
+		{desc: "unknown application identifier when lenient", mode: Mode3, validation: ValidationLenient, inputData: unknownAI},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var warnings []Warning
+
+			encoder, err := NewEncoder(tc.mode, WithValidation(tc.validation), WithWarnings(func(w Warning) { warnings = append(warnings, w) }))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// WithWarnings makes ValidationNone replace postcode characters
+			// too, so it is left out there.
+			if tc.validation == ValidationNone {
+				if encoder, err = NewEncoder(tc.mode); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err = encoder.Encode(tc.inputData)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected %v, got %v", tc.expErr, err)
+			}
+
+			if len(warnings) != tc.expWarnings {
+				t.Errorf("expected %d warnings, got %v", tc.expWarnings, warnings)
+			}
+		})
+	}
+
+	if _, err := NewEncoder(Mode2, WithValidation(Validation(99))); err == nil {
+		t.Error("expected an error for an unknown validation profile")
+	}
+}